@@ -18,6 +18,9 @@ func main() {
 	config.ConnectDB()
 	db := config.DB
 
+	// pastikan role superadmin sudah ada
+	config.SeedRoles()
+
 	reader := bufio.NewReader(os.Stdin)
 
 	// Input Username
@@ -46,12 +49,20 @@ func main() {
 		Username:   username,
 		Email:      email,
 		Password:   string(hashedPassword),
-		IsAdmin:    true,
 		IsVerified: true,
 	}
 
 	// Buat admin jika belum ada (idempotent)
 	db.FirstOrCreate(&admin, models.User{Username: admin.Username})
 
+	var role models.Role
+	if err := db.Where("name = ?", models.RoleSuperAdmin).First(&role).Error; err != nil {
+		log.Fatal("❌ Role superadmin tidak ditemukan:", err)
+	}
+
+	if err := db.Model(&admin).Association("Roles").Append(&role); err != nil {
+		log.Fatal("❌ Gagal memberi role superadmin:", err)
+	}
+
 	fmt.Println("✅ Admin berhasil dibuat:", admin.Username)
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// generate private key JWT baru, nama file = kid
+// contoh: go run ./cmd/jwtkey -alg EdDSA -dir internal/configs/keys
+func main() {
+	alg := flag.String("alg", "EdDSA", "algoritma key: EdDSA atau RS256")
+	dir := flag.String("dir", "internal/configs/keys", "folder penyimpanan key")
+	kid := flag.String("kid", time.Now().Format("20060102-150405"), "key id")
+	force := flag.Bool("force", false, "timpa file key yang sudah ada")
+	flag.Parse()
+
+	var key crypto.Signer
+	var err error
+
+	switch *alg {
+	case "EdDSA":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	case "RS256":
+		key, err = rsa.GenerateKey(rand.Reader, 3072)
+	default:
+		log.Fatalf("❌ Algoritma %q tidak didukung", *alg)
+	}
+	if err != nil {
+		log.Fatal("❌ Gagal generate key:", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		log.Fatal("❌ Gagal encode key:", err)
+	}
+
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		log.Fatal("❌ Gagal membuat folder key:", err)
+	}
+
+	path := filepath.Join(*dir, *kid+".pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	// default menolak menimpa key lama, token yang ditandatangani key itu
+	// tidak bisa diverifikasi lagi kalau filenya tertimpa
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if *force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		log.Fatalf("❌ Key %s sudah ada, pakai -kid lain atau -force untuk menimpa", path)
+	}
+	if err != nil {
+		log.Fatal("❌ Gagal menyimpan key:", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		log.Fatal("❌ Gagal menyimpan key:", err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal("❌ Gagal menyimpan key:", err)
+	}
+
+	fmt.Printf("✅ Key %s (%s) tersimpan di %s\n", *kid, *alg, path)
+	fmt.Printf("   Set JWT_ACTIVE_KID=%s untuk mulai memakai key ini\n", *kid)
+}
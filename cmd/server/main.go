@@ -1,10 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	config "mmgrapp/internal/configs"
+	"mmgrapp/internal/repositories"
 	"mmgrapp/internal/routes"
+	"mmgrapp/internal/workers"
+	"mmgrapp/pkg/utils"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,18 +23,62 @@ import (
 func main() {
 	config.LoadEnv()
 
+	// key JWT wajib ada sebelum server menerima request
+	keysDir := config.GetEnv("JWT_KEYS_DIR", "internal/configs/keys")
+	if err := utils.LoadJWTKeys(keysDir, config.GetEnv("JWT_ACTIVE_KID", "")); err != nil {
+		log.Fatal("❌ Gagal memuat JWT key: ", err)
+	}
+
 	config.ConnectDB()
 
+	mailer, err := config.NewMailer()
+	if err != nil {
+		log.Fatal("❌ Gagal menyiapkan mailer: ", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// worker pengirim email dari outbox
+	var wg sync.WaitGroup
+	emailWorker := workers.NewEmailWorker(repositories.NewEmailOutboxRepository(config.DB), mailer, workers.DefaultEmailWorkerConfig())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		emailWorker.Run(ctx)
+	}()
+
 	r := gin.Default()
+	// gin default percaya semua proxy, IP client hanya diambil dari header
+	// forwarded jika request datang dari proxy yang dikonfigurasi
+	if err := r.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		log.Fatal("❌ TRUSTED_PROXIES tidak valid: ", err)
+	}
 	routes.SetupRoutes(r)
 
 	host := config.GetEnv("APP_HOST", "localhost")
 	port := config.GetEnv("APP_PORT", "8080")
 	address := fmt.Sprintf("%s:%s", host, port)
 
-	fmt.Printf("🚀 Server siap dijalankan di http://%s\n", address)
+	server := &http.Server{Addr: address, Handler: r}
+
+	go func() {
+		fmt.Printf("🚀 Server siap dijalankan di http://%s\n", address)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("❌ Gagal menjalankan server:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	fmt.Println("🛑 Mematikan server...")
 
-	if err := r.Run(address); err != nil {
-		log.Fatal("❌ Gagal menjalankan server:", err)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("❌ Gagal mematikan server dengan rapi:", err)
 	}
+
+	// tunggu worker menyelesaikan email yang sedang dikirim
+	wg.Wait()
 }
@@ -35,6 +35,12 @@ func Migrate() {
 		{"Expense", &models.Expense{}},
 		{"UserOTP", &models.UserOTP{}},
 		{"RefreshToken", &models.RefreshToken{}},
+		{"LoginAttempt", &models.LoginAttempt{}},
+		{"UserMFA", &models.UserMFA{}},
+		{"MFARecoveryCode", &models.MFARecoveryCode{}},
+		{"EmailOutbox", &models.EmailOutbox{}},
+		{"Permission", &models.Permission{}},
+		{"Role", &models.Role{}},
 	}
 
 	for _, table := range tables {
@@ -46,5 +52,127 @@ func Migrate() {
 		}
 	}
 
+	invalidatePlaintextRefreshTokens()
+	ensureSingleDefaultPeriod()
+	SeedRoles()
+	migrateLegacyAdmins()
+
 	fmt.Println("✅ Semua migrasi selesai!")
 }
+
+// SeedRoles membuat role & permission bawaan (idempotent)
+func SeedRoles() {
+	for name, description := range models.DefaultPermissions {
+		permission := models.Permission{Name: name}
+		if err := DB.Where(models.Permission{Name: name}).
+			Assign(models.Permission{Description: description}).
+			FirstOrCreate(&permission).Error; err != nil {
+			fmt.Printf("❌ Gagal seed permission %s: %v\n", name, err)
+		}
+	}
+
+	for name, permissionNames := range models.DefaultRoles {
+		role := models.Role{Name: name}
+		if err := DB.Where(models.Role{Name: name}).FirstOrCreate(&role).Error; err != nil {
+			fmt.Printf("❌ Gagal seed role %s: %v\n", name, err)
+			continue
+		}
+
+		var permissions []models.Permission
+		if err := DB.Where("name IN ?", permissionNames).Find(&permissions).Error; err != nil {
+			fmt.Printf("❌ Gagal ambil permission role %s: %v\n", name, err)
+			continue
+		}
+
+		if err := DB.Model(&role).Association("Permissions").Replace(permissions); err != nil {
+			fmt.Printf("❌ Gagal set permission role %s: %v\n", name, err)
+		}
+	}
+
+	fmt.Println("✅ Role & permission bawaan tersedia")
+}
+
+// migrateLegacyAdmins memberi role superadmin ke user dengan kolom lama is_admin = true.
+// Flag lama dimatikan dalam transaksi yang sama, jadi migrasi hanya berlaku sekali
+// dan role yang kemudian dicabut admin tidak kembali di startup berikutnya.
+func migrateLegacyAdmins() {
+	if !DB.Migrator().HasColumn(&models.User{}, "is_admin") {
+		return
+	}
+
+	var role models.Role
+	if err := DB.Where("name = ?", models.RoleSuperAdmin).First(&role).Error; err != nil {
+		fmt.Printf("❌ Role %s tidak ditemukan: %v\n", models.RoleSuperAdmin, err)
+		return
+	}
+
+	var userIDs []int
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("is_admin = ?", true).Pluck("id", &userIDs).Error; err != nil {
+			return fmt.Errorf("membaca admin lama: %w", err)
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		for _, id := range userIDs {
+			user := models.User{ID: id}
+			if err := tx.Model(&user).Association("Roles").Append(&role); err != nil {
+				return fmt.Errorf("memberi role %s ke user %d: %w", models.RoleSuperAdmin, id, err)
+			}
+		}
+
+		return tx.Model(&models.User{}).Where("id IN ?", userIDs).Update("is_admin", false).Error
+	})
+	if err != nil {
+		fmt.Printf("❌ Gagal memindah admin lama: %v\n", err)
+		return
+	}
+
+	if len(userIDs) > 0 {
+		fmt.Printf("✅ %d admin lama dipindah ke role %s\n", len(userIDs), models.RoleSuperAdmin)
+	}
+}
+
+// invalidatePlaintextRefreshTokens hapus refresh token lama yang masih tersimpan plaintext.
+// Token sekarang disimpan sebagai SHA-256 hex (tanpa titik), sedangkan JWT selalu mengandung titik.
+func invalidatePlaintextRefreshTokens() {
+	result := DB.Where("token LIKE ?", "%.%").Delete(&models.RefreshToken{})
+	if result.Error != nil {
+		fmt.Printf("❌ Gagal membersihkan refresh token plaintext: %v\n", result.Error)
+		return
+	}
+
+	if result.RowsAffected > 0 {
+		fmt.Printf("✅ %d refresh token plaintext dihapus, user terkait perlu login ulang\n", result.RowsAffected)
+	}
+}
+
+// ensureSingleDefaultPeriod pasang unique partial index supaya DB sendiri menjamin
+// maksimal satu periode default per user. Data lama yang default-nya dobel
+// dirapikan dulu: hanya periode default terbaru yang dipertahankan.
+func ensureSingleDefaultPeriod() {
+	result := DB.Exec(`UPDATE periods SET is_default = false
+		WHERE is_default = true AND deleted_at IS NULL
+		AND id NOT IN (
+			SELECT MAX(id) FROM periods
+			WHERE is_default = true AND deleted_at IS NULL
+			GROUP BY user_id
+		)`)
+	if result.Error != nil {
+		fmt.Printf("❌ Gagal merapikan periode default ganda: %v\n", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		fmt.Printf("✅ %d periode default ganda dilepas\n", result.RowsAffected)
+	}
+
+	err := DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_periods_user_default
+		ON periods (user_id) WHERE is_default = true AND deleted_at IS NULL`).Error
+	if err != nil {
+		fmt.Printf("❌ Gagal membuat index periode default: %v\n", err)
+		return
+	}
+
+	fmt.Println("✅ Index satu periode default per user tersedia")
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"mmgrapp/internal/models"
+	"mmgrapp/pkg/utils"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// useTestDB ganti DB global dengan sqlite sementara selama test
+func useTestDB(t *testing.T, models ...interface{}) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := DB
+	DB = db
+	t.Cleanup(func() {
+		DB = previous
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+}
+
+func TestInvalidatePlaintextRefreshTokens(t *testing.T) {
+	useTestDB(t, &models.RefreshToken{})
+
+	expiresAt := time.Now().Add(time.Hour)
+	hashed := utils.HashToken("header.payload.signature")
+	DB.Create(&models.RefreshToken{UserID: 1, Token: "header.payload.signature", ExpiresAt: expiresAt})
+	DB.Create(&models.RefreshToken{UserID: 1, Token: hashed, ExpiresAt: expiresAt})
+
+	invalidatePlaintextRefreshTokens()
+
+	var tokens []models.RefreshToken
+	DB.Find(&tokens)
+	if len(tokens) != 1 || tokens[0].Token != hashed {
+		t.Fatalf("token tersisa = %+v, want hanya token digest", tokens)
+	}
+}
+
+func TestMigrateLegacyAdminsRunsOnce(t *testing.T) {
+	useTestDB(t, &models.User{})
+
+	// skema lama: kolom is_admin sudah tidak ada di model tapi masih di tabel
+	if err := DB.Exec("ALTER TABLE users ADD COLUMN is_admin boolean DEFAULT false").Error; err != nil {
+		t.Fatal(err)
+	}
+	admin := models.User{Username: "admin", Email: "admin@example.com", Password: "x"}
+	if err := DB.Create(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	DB.Exec("UPDATE users SET is_admin = true WHERE id = ?", admin.ID)
+
+	roleNames := func() []string {
+		var names []string
+		DB.Table("roles").
+			Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+			Where("user_roles.user_id = ?", admin.ID).
+			Pluck("roles.name", &names)
+		return names
+	}
+
+	Migrate()
+	if names := roleNames(); len(names) != 1 || names[0] != models.RoleSuperAdmin {
+		t.Fatalf("role setelah migrasi = %v, want [%s]", names, models.RoleSuperAdmin)
+	}
+
+	// role dicabut admin lain, migrasi berikutnya tidak boleh mengembalikannya
+	if err := DB.Model(&admin).Association("Roles").Clear(); err != nil {
+		t.Fatal(err)
+	}
+
+	Migrate()
+	if names := roleNames(); len(names) != 0 {
+		t.Fatalf("role kembali setelah migrasi ulang: %v", names)
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -22,3 +23,17 @@ func GetEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// TrustedProxies IP / CIDR reverse proxy (TRUSTED_PROXIES, dipisah koma) yang
+// header X-Forwarded-For-nya dipercaya. Default kosong: IP client diambil dari
+// koneksi langsung, header forwarded dari client diabaikan supaya rate limit &
+// lock per IP tidak bisa diakali.
+func TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(GetEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"mmgrapp/pkg/utils"
+	"strconv"
+)
+
+// NewMailer membuat Mailer sesuai MAIL_DRIVER: smtp (default), file, atau memory
+func NewMailer() (utils.Mailer, error) {
+	from := "MMGRAPP <" + GetEnv("SENDER_EMAIL", "") + ">"
+
+	switch driver := GetEnv("MAIL_DRIVER", "smtp"); driver {
+	case "smtp":
+		if GetEnv("SMTP_HOST", "") == "" {
+			return nil, fmt.Errorf("SMTP_HOST belum diisi, pakai MAIL_DRIVER=file untuk dev lokal")
+		}
+
+		port, err := strconv.Atoi(GetEnv("SMTP_PORT", "587"))
+		if err != nil {
+			return nil, fmt.Errorf("SMTP_PORT tidak valid: %w", err)
+		}
+
+		return utils.NewSMTPMailer(utils.SMTPConfig{
+			Host:     GetEnv("SMTP_HOST", ""),
+			Port:     port,
+			Username: GetEnv("SMTP_EMAIL", ""),
+			Password: GetEnv("SMTP_PASSWORD", ""),
+			From:     from,
+			TLSMode:  GetEnv("SMTP_TLS", ""),
+			Insecure: GetEnv("SMTP_TLS_INSECURE", "false") == "true",
+		})
+	case "file":
+		return utils.NewFileMailer(from, GetEnv("MAIL_FILE_DIR", "tmp/mails"))
+	case "memory":
+		return utils.NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("MAIL_DRIVER %q tidak dikenal (smtp, file, memory)", driver)
+	}
+}
@@ -0,0 +1,8 @@
+package dto
+
+// AccountRequest payload untuk create / update akun (dompet, bank, e-wallet, cash)
+type AccountRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Type        string `json:"type" binding:"required,oneof=cash bank e_wallet other"`
+	Description string `json:"description"`
+}
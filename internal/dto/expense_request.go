@@ -0,0 +1,11 @@
+package dto
+
+// ExpenseRequest payload untuk create / update pengeluaran
+type ExpenseRequest struct {
+	PeriodID    int     `json:"period_id" binding:"required"`
+	AccountID   int     `json:"account_id" binding:"required"`
+	Date        string  `json:"date" binding:"required"` // format YYYY-MM-DD
+	Category    string  `json:"category" binding:"required"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+}
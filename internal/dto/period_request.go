@@ -0,0 +1,9 @@
+package dto
+
+// PeriodRequest payload untuk create / update periode
+type PeriodRequest struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	StartDate string `json:"start_date" binding:"required"` // format YYYY-MM-DD
+	EndDate   string `json:"end_date" binding:"required"`   // format YYYY-MM-DD
+	IsDefault bool   `json:"is_default"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+// ProfileRequest payload untuk update profile user yang login
+type ProfileRequest struct {
+	FirstName  string `json:"first_name" binding:"required,max=100"`
+	MiddleName string `json:"middle_name" binding:"max=100"`
+	LastName   string `json:"last_name" binding:"max=100"`
+	Locale     string `json:"locale" binding:"omitempty,oneof=id en"` // kosong = tidak diubah
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// ClientInfo informasi perangkat yang melakukan login / refresh
+type ClientInfo struct {
+	UserAgent   string
+	IPAddress   string
+	DeviceLabel string
+}
+
+type SessionResponse struct {
+	ID           int       `json:"id"`
+	DeviceLabel  string    `json:"device_label"`
+	UserAgent    string    `json:"user_agent"`
+	IPAddress    string    `json:"ip_address"`
+	LastActiveAt time.Time `json:"last_active_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+type SummaryResponse struct {
+	PeriodID          int               `json:"period_id,omitempty"`
+	StartDate         string            `json:"start_date,omitempty"`
+	EndDate           string            `json:"end_date,omitempty"`
+	TotalIncome       float64           `json:"total_income"`
+	TotalExpense      float64           `json:"total_expense"`
+	NetBalance        float64           `json:"net_balance"`
+	Accounts          []AccountSummary  `json:"accounts"`
+	IncomeCategories  []CategorySummary `json:"income_categories"`
+	ExpenseCategories []CategorySummary `json:"expense_categories"`
+}
+
+type AccountSummary struct {
+	AccountID    int     `json:"account_id"`
+	AccountName  string  `json:"account_name"`
+	AccountType  string  `json:"account_type"`
+	TotalIncome  float64 `json:"total_income"`
+	TotalExpense float64 `json:"total_expense"`
+	Balance      float64 `json:"balance"`
+}
+
+type CategorySummary struct {
+	Category string  `json:"category"`
+	Total    float64 `json:"total"`
+	Count    int64   `json:"count"`
+}
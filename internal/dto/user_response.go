@@ -1,8 +1,34 @@
 package dto
 
+import "mmgrapp/internal/models"
+
 type UserResponse struct {
-	ID         int    `json:"id"`
-	Username   string `json:"username"`
-	Email      string `json:"email"`
-	IsVerified bool   `json:"is_verified"`
+	ID         int              `json:"id"`
+	Username   string           `json:"username"`
+	Email      string           `json:"email"`
+	IsVerified bool             `json:"is_verified"`
+	Profile    *ProfileResponse `json:"profile,omitempty"`
+}
+
+type ProfileResponse struct {
+	FirstName  string `json:"first_name"`
+	MiddleName string `json:"middle_name"`
+	LastName   string `json:"last_name"`
+	FullName   string `json:"full_name"`
+	Locale     string `json:"locale"`
+}
+
+// NewProfileResponse mapping models.Profile ke response, nil jika profile belum ada
+func NewProfileResponse(profile *models.Profile) *ProfileResponse {
+	if profile == nil {
+		return nil
+	}
+
+	return &ProfileResponse{
+		FirstName:  profile.FirstName,
+		MiddleName: profile.MiddleName,
+		LastName:   profile.LastName,
+		FullName:   profile.FullName,
+		Locale:     profile.Locale,
+	}
 }
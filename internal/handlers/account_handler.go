@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	accountService services.AccountService
+}
+
+func NewAccountHandler(accountService services.AccountService) *AccountHandler {
+	return &AccountHandler{accountService: accountService}
+}
+
+/* ================= CREATE ================= */
+
+func (h *AccountHandler) Create(ctx *gin.Context) {
+	var req dto.AccountRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := h.accountService.Create(ctx, ctx.GetInt("user_id"), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Akun berhasil ditambahkan",
+		"data":    account,
+	})
+}
+
+/* ================= LIST ================= */
+
+func (h *AccountHandler) List(ctx *gin.Context) {
+	includeInactive := ctx.Query("include_inactive") == "true"
+
+	accounts, err := h.accountService.List(ctx, ctx.GetInt("user_id"), includeInactive)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get data akun berhasil",
+		"data":    accounts,
+	})
+}
+
+/* ================= DETAIL ================= */
+
+func (h *AccountHandler) GetByID(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := h.accountService.GetByID(ctx, ctx.GetInt("user_id"), id)
+	if err != nil {
+		ctx.JSON(accountErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get detail akun berhasil",
+		"data":    account,
+	})
+}
+
+/* ================= UPDATE ================= */
+
+func (h *AccountHandler) Update(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req dto.AccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := h.accountService.Update(ctx, ctx.GetInt("user_id"), id, req)
+	if err != nil {
+		ctx.JSON(accountErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Akun berhasil diperbarui",
+		"data":    account,
+	})
+}
+
+/* ================= ARCHIVE / ACTIVATE ================= */
+
+func (h *AccountHandler) Archive(ctx *gin.Context) {
+	h.setActive(ctx, false, "Akun berhasil diarsipkan")
+}
+
+func (h *AccountHandler) Activate(ctx *gin.Context) {
+	h.setActive(ctx, true, "Akun berhasil diaktifkan kembali")
+}
+
+func (h *AccountHandler) setActive(ctx *gin.Context, isActive bool, message string) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := h.accountService.SetActive(ctx, ctx.GetInt("user_id"), id, isActive)
+	if err != nil {
+		ctx.JSON(accountErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    account,
+	})
+}
+
+/* ================= DELETE ================= */
+
+func (h *AccountHandler) Delete(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.accountService.Delete(ctx, ctx.GetInt("user_id"), id); err != nil {
+		ctx.JSON(accountErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Akun berhasil dihapus",
+	})
+}
+
+func accountErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrAccountNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrAccountHasTransactions):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
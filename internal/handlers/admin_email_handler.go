@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminEmailHandler struct {
+	emailOutboxService services.EmailOutboxService
+}
+
+func NewAdminEmailHandler(emailOutboxService services.EmailOutboxService) *AdminEmailHandler {
+	return &AdminEmailHandler{emailOutboxService: emailOutboxService}
+}
+
+/* ================= LIST ================= */
+
+func (h *AdminEmailHandler) List(ctx *gin.Context) {
+	var (
+		filter repositories.EmailOutboxFilter
+		err    error
+	)
+
+	if filter.Page, err = queryInt(ctx, "page"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.Limit, err = queryInt(ctx, "limit"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter.Status = ctx.Query("status")
+	switch filter.Status {
+	case "", models.EmailStatusPending, models.EmailStatusSending, models.EmailStatusSent, models.EmailStatusDead:
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "status harus salah satu dari pending, sending, sent, dead"})
+		return
+	}
+	filter.ToEmail = ctx.Query("to")
+
+	emails, meta, err := h.emailOutboxService.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get data email berhasil",
+		"data":    emails,
+		"meta":    meta,
+	})
+}
+
+/* ================= DETAIL ================= */
+
+func (h *AdminEmailHandler) GetByID(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	email, err := h.emailOutboxService.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(adminEmailErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get detail email berhasil",
+		"data":    email,
+	})
+}
+
+/* ================= RETRY ================= */
+
+func (h *AdminEmailHandler) Retry(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	email, err := h.emailOutboxService.Retry(ctx, id)
+	if err != nil {
+		ctx.JSON(adminEmailErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Email dijadwalkan untuk dikirim ulang",
+		"data":    email,
+	})
+}
+
+func adminEmailErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrEmailNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrEmailNotDead), errors.Is(err, services.ErrEmailExpired):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminUserHandler struct {
+	adminUserService services.AdminUserService
+}
+
+func NewAdminUserHandler(adminUserService services.AdminUserService) *AdminUserHandler {
+	return &AdminUserHandler{adminUserService: adminUserService}
+}
+
+/* ================= LIST ================= */
+
+func (h *AdminUserHandler) List(ctx *gin.Context) {
+	var (
+		filter repositories.UserFilter
+		err    error
+	)
+
+	if filter.Page, err = queryInt(ctx, "page"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.Limit, err = queryInt(ctx, "limit"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.IsActive, err = queryBool(ctx, "is_active"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.IsVerified, err = queryBool(ctx, "is_verified"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Search = ctx.Query("q")
+
+	users, meta, err := h.adminUserService.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get data user berhasil",
+		"data":    users,
+		"meta":    meta,
+	})
+}
+
+/* ================= DETAIL ================= */
+
+func (h *AdminUserHandler) GetByID(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.adminUserService.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(adminUserErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get detail user berhasil",
+		"data":    user,
+	})
+}
+
+/* ================= STATUS ================= */
+
+func (h *AdminUserHandler) Deactivate(ctx *gin.Context) {
+	h.setActive(ctx, false, "User berhasil dinonaktifkan")
+}
+
+func (h *AdminUserHandler) Activate(ctx *gin.Context) {
+	h.setActive(ctx, true, "User berhasil diaktifkan kembali")
+}
+
+func (h *AdminUserHandler) setActive(ctx *gin.Context, isActive bool, message string) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.adminUserService.SetActive(ctx, ctx.GetInt("user_id"), id, isActive)
+	if err != nil {
+		ctx.JSON(adminUserErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    user,
+	})
+}
+
+func (h *AdminUserHandler) Verify(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.adminUserService.Verify(ctx, ctx.GetInt("user_id"), id)
+	if err != nil {
+		ctx.JSON(adminUserErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "User berhasil diverifikasi",
+		"data":    user,
+	})
+}
+
+/* ================= FORCE RESET PASSWORD ================= */
+
+func (h *AdminUserHandler) ForcePasswordReset(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.adminUserService.ForcePasswordReset(ctx, ctx.GetInt("user_id"), id); err != nil {
+		ctx.JSON(adminUserErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Password user direset, OTP reset password telah dikirim ke email user",
+	})
+}
+
+/* ================= DELETE ================= */
+
+func (h *AdminUserHandler) Delete(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.adminUserService.Delete(ctx, ctx.GetInt("user_id"), id); err != nil {
+		ctx.JSON(adminUserErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "User berhasil dihapus",
+	})
+}
+
+func adminUserErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrCannotModifySelf):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
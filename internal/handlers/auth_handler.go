@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"math"
+	"mmgrapp/internal/dto"
 	"mmgrapp/internal/services"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,8 +22,9 @@ func NewAuthHandler(authService services.AuthService) *AuthHandler {
 /* ================= LOGIN ================= */
 
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username    string `json:"username" binding:"required"`
+	Password    string `json:"password" binding:"required"`
+	DeviceLabel string `json:"device_label" binding:"max=100"`
 }
 
 func (h *AuthHandler) Login(ctx *gin.Context) {
@@ -37,8 +42,46 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	data, err := h.authService.Login(ctx, req.Username, req.Password)
+	data, err := h.authService.Login(ctx, req.Username, req.Password, clientInfo(ctx, req.DeviceLabel))
 	if err != nil {
+		if respondStatusError(ctx, err) || respondLoginLockError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	message := "Login berhasil"
+	if result, ok := data.(map[string]interface{}); ok && result["mfa_required"] == true {
+		message = "Masukkan kode MFA untuk menyelesaikan login"
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    data,
+	})
+}
+
+type VerifyMFARequest struct {
+	MFAToken    string `json:"mfa_token" binding:"required"`
+	Code        string `json:"code" binding:"required"`
+	DeviceLabel string `json:"device_label" binding:"max=100"`
+}
+
+// VerifyMFA langkah kedua login untuk user dengan MFA aktif
+func (h *AuthHandler) VerifyMFA(ctx *gin.Context) {
+	var req VerifyMFARequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := h.authService.VerifyMFA(ctx, req.MFAToken, req.Code, clientInfo(ctx, req.DeviceLabel))
+	if err != nil {
+		if respondStatusError(ctx, err) || respondLoginLockError(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
@@ -49,6 +92,85 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 	})
 }
 
+/* ================= UNLOCK ACCOUNT ================= */
+
+type RequestUnlockRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+func (h *AuthHandler) RequestUnlock(ctx *gin.Context) {
+	var req RequestUnlockRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.RequestUnlock(ctx, req.Email); err != nil {
+		if respondOTPCooldown(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "OTP buka kunci akun telah dikirim ke email",
+	})
+}
+
+type UnlockAccountRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	OTP   string `json:"otp" binding:"required"`
+}
+
+func (h *AuthHandler) UnlockAccount(ctx *gin.Context) {
+	var req UnlockAccountRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.UnlockAccount(ctx, req.Email, req.OTP); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Akun berhasil dibuka, silakan login kembali",
+	})
+}
+
+/* ================= CHANGE PASSWORD ================= */
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
+func (h *AuthHandler) ChangePassword(ctx *gin.Context) {
+	var req ChangePasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.authService.ChangePassword(ctx, ctx.GetInt("user_id"), ctx.GetString("jti"), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if respondLoginLockError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Password berhasil diubah, sesi di perangkat lain telah diakhiri",
+	})
+}
+
 /* ================= FORGOT PASSWORD ================= */
 
 type ForgotPasswordRequest struct {
@@ -65,6 +187,9 @@ func (h *AuthHandler) ForgotPassword(ctx *gin.Context) {
 
 	err := h.authService.ForgotPassword(ctx, req.Email)
 	if err != nil {
+		if respondOTPCooldown(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -114,8 +239,11 @@ func (h *AuthHandler) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	newRefreshToken, err := h.authService.RefreshToken(ctx, req.RefreshToken)
+	newRefreshToken, err := h.authService.RefreshToken(ctx, req.RefreshToken, clientInfo(ctx, ""))
 	if err != nil {
+		if respondStatusError(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -144,3 +272,110 @@ func (h *AuthHandler) RevokeToken(ctx *gin.Context) {
 		"message": "Logout Berhasil",
 	})
 }
+
+func (h *AuthHandler) LogoutAll(ctx *gin.Context) {
+	err := h.authService.LogoutAll(ctx, ctx.GetInt("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Logout dari semua perangkat berhasil",
+	})
+}
+
+/* ================= SESSIONS ================= */
+
+func (h *AuthHandler) ListSessions(ctx *gin.Context) {
+	sessions, err := h.authService.ListSessions(ctx, ctx.GetInt("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get data sesi berhasil",
+		"data":    sessions,
+	})
+}
+
+func (h *AuthHandler) RevokeSession(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.RevokeSession(ctx, ctx.GetInt("user_id"), id); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrSessionNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Sesi berhasil diakhiri",
+	})
+}
+
+// clientInfo ambil User-Agent & IP dari request
+func clientInfo(ctx *gin.Context, deviceLabel string) dto.ClientInfo {
+	return dto.ClientInfo{
+		UserAgent:   ctx.Request.UserAgent(),
+		IPAddress:   ctx.ClientIP(),
+		DeviceLabel: deviceLabel,
+	}
+}
+
+// respondStatusError kirim 403 + kode jika err adalah error status akun
+func respondStatusError(ctx *gin.Context, err error) bool {
+	var statusErr *services.StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	ctx.JSON(http.StatusForbidden, gin.H{
+		"error": statusErr.Message,
+		"code":  statusErr.Code,
+	})
+	return true
+}
+
+// respondLoginLockError kirim 429 + Retry-After jika login sedang dijeda / dikunci
+func respondLoginLockError(ctx *gin.Context, err error) bool {
+	var lockErr *services.LoginLockError
+	if !errors.As(err, &lockErr) {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(lockErr.RetryAfter.Seconds()))
+	ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+	ctx.JSON(http.StatusTooManyRequests, gin.H{
+		"error":        lockErr.Message,
+		"code":         lockErr.Code,
+		"retry_after":  retryAfter,
+		"locked_until": lockErr.LockedUntil,
+		"unlockable":   lockErr.Unlockable,
+	})
+	return true
+}
+
+// respondOTPCooldown kirim 429 + Retry-After jika OTP baru diminta terlalu cepat
+func respondOTPCooldown(ctx *gin.Context, err error) bool {
+	var cooldownErr *services.OTPCooldownError
+	if !errors.As(err, &cooldownErr) {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(cooldownErr.RetryAfter.Seconds()))
+	ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+	ctx.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       cooldownErr.Error(),
+		"code":        "OTP_COOLDOWN",
+		"retry_after": retryAfter,
+	})
+	return true
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	config "mmgrapp/internal/configs"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordingAuthService catat ClientInfo yang diterima Login, dasar key lock per IP
+type recordingAuthService struct {
+	services.AuthService
+	clients []dto.ClientInfo
+}
+
+func (s *recordingAuthService) Login(ctx context.Context, username, password string, client dto.ClientInfo) (interface{}, error) {
+	s.clients = append(s.clients, client)
+	return nil, errors.New("email/username atau password salah")
+}
+
+func TestLoginLockKeyIgnoresForgedForwardedFor(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "")
+	gin.SetMode(gin.TestMode)
+
+	// pengaturan proxy sama seperti cmd/server
+	r := gin.New()
+	if err := r.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		t.Fatal(err)
+	}
+
+	authService := &recordingAuthService{}
+	r.POST("/login", NewAuthHandler(authService).Login)
+
+	for _, forwarded := range []string{"1.1.1.1", "198.51.100.23"} {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username":"alice","password":"wrong"}`))
+		req.RemoteAddr = "203.0.113.7:40000"
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwarded)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if len(authService.clients) != 2 {
+		t.Fatalf("Login dipanggil %d kali, want 2", len(authService.clients))
+	}
+	for _, client := range authService.clients {
+		if client.IPAddress != "203.0.113.7" {
+			t.Fatalf("IP untuk lock = %q, want IP koneksi 203.0.113.7", client.IPAddress)
+		}
+	}
+}
@@ -1 +1,166 @@
 package handlers
+
+import (
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExpenseHandler struct {
+	expenseService services.ExpenseService
+}
+
+func NewExpenseHandler(expenseService services.ExpenseService) *ExpenseHandler {
+	return &ExpenseHandler{expenseService: expenseService}
+}
+
+/* ================= CREATE ================= */
+
+func (h *ExpenseHandler) Create(ctx *gin.Context) {
+	var req dto.ExpenseRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expense, err := h.expenseService.Create(ctx, ctx.GetInt("user_id"), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Pengeluaran berhasil ditambahkan",
+		"data":    expense,
+	})
+}
+
+/* ================= LIST ================= */
+
+func (h *ExpenseHandler) List(ctx *gin.Context) {
+	filter, err := parseExpenseFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expenses, meta, err := h.expenseService.List(ctx, ctx.GetInt("user_id"), filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get data pengeluaran berhasil",
+		"data":    expenses,
+		"meta":    meta,
+	})
+}
+
+func parseExpenseFilter(ctx *gin.Context) (repositories.ExpenseFilter, error) {
+	var (
+		filter repositories.ExpenseFilter
+		err    error
+	)
+
+	if filter.Page, err = queryInt(ctx, "page"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = queryInt(ctx, "limit"); err != nil {
+		return filter, err
+	}
+	if filter.PeriodID, err = queryInt(ctx, "period_id"); err != nil {
+		return filter, err
+	}
+	if filter.AccountID, err = queryInt(ctx, "account_id"); err != nil {
+		return filter, err
+	}
+	if filter.StartDate, err = queryDate(ctx, "start_date"); err != nil {
+		return filter, err
+	}
+	if filter.EndDate, err = queryDate(ctx, "end_date"); err != nil {
+		return filter, err
+	}
+	filter.Category = ctx.Query("category")
+
+	return filter, nil
+}
+
+/* ================= DETAIL ================= */
+
+func (h *ExpenseHandler) GetByID(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expense, err := h.expenseService.GetByID(ctx, ctx.GetInt("user_id"), id)
+	if err != nil {
+		ctx.JSON(expenseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get detail pengeluaran berhasil",
+		"data":    expense,
+	})
+}
+
+/* ================= UPDATE ================= */
+
+func (h *ExpenseHandler) Update(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req dto.ExpenseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expense, err := h.expenseService.Update(ctx, ctx.GetInt("user_id"), id, req)
+	if err != nil {
+		ctx.JSON(expenseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Pengeluaran berhasil diperbarui",
+		"data":    expense,
+	})
+}
+
+/* ================= DELETE ================= */
+
+func (h *ExpenseHandler) Delete(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.expenseService.Delete(ctx, ctx.GetInt("user_id"), id); err != nil {
+		ctx.JSON(expenseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Pengeluaran berhasil dihapus",
+	})
+}
+
+func expenseErrorStatus(err error) int {
+	if errors.Is(err, services.ErrExpenseNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
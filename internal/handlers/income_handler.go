@@ -1 +1,166 @@
 package handlers
+
+import (
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IncomeHandler struct {
+	incomeService services.IncomeService
+}
+
+func NewIncomeHandler(incomeService services.IncomeService) *IncomeHandler {
+	return &IncomeHandler{incomeService: incomeService}
+}
+
+/* ================= CREATE ================= */
+
+func (h *IncomeHandler) Create(ctx *gin.Context) {
+	var req dto.IncomeRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	income, err := h.incomeService.Create(ctx, ctx.GetInt("user_id"), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Pemasukan berhasil ditambahkan",
+		"data":    income,
+	})
+}
+
+/* ================= LIST ================= */
+
+func (h *IncomeHandler) List(ctx *gin.Context) {
+	filter, err := parseIncomeFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	incomes, meta, err := h.incomeService.List(ctx, ctx.GetInt("user_id"), filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get data pemasukan berhasil",
+		"data":    incomes,
+		"meta":    meta,
+	})
+}
+
+func parseIncomeFilter(ctx *gin.Context) (repositories.IncomeFilter, error) {
+	var (
+		filter repositories.IncomeFilter
+		err    error
+	)
+
+	if filter.Page, err = queryInt(ctx, "page"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = queryInt(ctx, "limit"); err != nil {
+		return filter, err
+	}
+	if filter.PeriodID, err = queryInt(ctx, "period_id"); err != nil {
+		return filter, err
+	}
+	if filter.AccountID, err = queryInt(ctx, "account_id"); err != nil {
+		return filter, err
+	}
+	if filter.StartDate, err = queryDate(ctx, "start_date"); err != nil {
+		return filter, err
+	}
+	if filter.EndDate, err = queryDate(ctx, "end_date"); err != nil {
+		return filter, err
+	}
+	filter.Category = ctx.Query("category")
+
+	return filter, nil
+}
+
+/* ================= DETAIL ================= */
+
+func (h *IncomeHandler) GetByID(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	income, err := h.incomeService.GetByID(ctx, ctx.GetInt("user_id"), id)
+	if err != nil {
+		ctx.JSON(incomeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get detail pemasukan berhasil",
+		"data":    income,
+	})
+}
+
+/* ================= UPDATE ================= */
+
+func (h *IncomeHandler) Update(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req dto.IncomeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	income, err := h.incomeService.Update(ctx, ctx.GetInt("user_id"), id, req)
+	if err != nil {
+		ctx.JSON(incomeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Pemasukan berhasil diperbarui",
+		"data":    income,
+	})
+}
+
+/* ================= DELETE ================= */
+
+func (h *IncomeHandler) Delete(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.incomeService.Delete(ctx, ctx.GetInt("user_id"), id); err != nil {
+		ctx.JSON(incomeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Pemasukan berhasil dihapus",
+	})
+}
+
+func incomeErrorStatus(err error) int {
+	if errors.Is(err, services.ErrIncomeNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"mmgrapp/pkg/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKS public key untuk verifikasi access token oleh service lain
+func JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, utils.CurrentSigner().JWKS())
+}
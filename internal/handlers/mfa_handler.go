@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"mmgrapp/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	mfaService services.MFAService
+}
+
+func NewMFAHandler(mfaService services.MFAService) *MFAHandler {
+	return &MFAHandler{mfaService: mfaService}
+}
+
+/* ================= STATUS ================= */
+
+func (h *MFAHandler) Status(ctx *gin.Context) {
+	status, err := h.mfaService.Status(ctx, ctx.GetInt("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get status MFA berhasil",
+		"data":    status,
+	})
+}
+
+/* ================= ENROLL ================= */
+
+func (h *MFAHandler) Setup(ctx *gin.Context) {
+	setup, err := h.mfaService.Setup(ctx, ctx.GetInt("user_id"))
+	if err != nil {
+		ctx.JSON(mfaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Scan QR code lalu konfirmasi dengan kode dari aplikasi authenticator",
+		"data":    setup,
+	})
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+func (h *MFAHandler) Enable(ctx *gin.Context) {
+	var req MFACodeRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.mfaService.Enable(ctx, ctx.GetInt("user_id"), req.Code)
+	if err != nil {
+		ctx.JSON(mfaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "MFA berhasil diaktifkan, simpan recovery code di tempat aman",
+		"data":    codes,
+	})
+}
+
+/* ================= DISABLE ================= */
+
+type DisableMFARequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+func (h *MFAHandler) Disable(ctx *gin.Context) {
+	var req DisableMFARequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.mfaService.Disable(ctx, ctx.GetInt("user_id"), req.Password, req.Code); err != nil {
+		if respondLoginLockError(ctx, err) {
+			return
+		}
+		ctx.JSON(mfaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "MFA berhasil dinonaktifkan",
+	})
+}
+
+/* ================= RECOVERY CODES ================= */
+
+func (h *MFAHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var req MFACodeRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(ctx, ctx.GetInt("user_id"), req.Code)
+	if err != nil {
+		if respondLoginLockError(ctx, err) {
+			return
+		}
+		ctx.JSON(mfaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Recovery code baru berhasil dibuat, code lama tidak berlaku lagi",
+		"data":    codes,
+	})
+}
+
+func mfaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrMFAAlreadyEnabled):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrMFANotEnabled), errors.Is(err, services.ErrMFASetupNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
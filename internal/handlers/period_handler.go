@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PeriodHandler struct {
+	periodService services.PeriodService
+}
+
+func NewPeriodHandler(periodService services.PeriodService) *PeriodHandler {
+	return &PeriodHandler{periodService: periodService}
+}
+
+/* ================= CREATE ================= */
+
+func (h *PeriodHandler) Create(ctx *gin.Context) {
+	var req dto.PeriodRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	period, err := h.periodService.Create(ctx, ctx.GetInt("user_id"), req)
+	if err != nil {
+		ctx.JSON(periodErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Periode berhasil ditambahkan",
+		"data":    period,
+	})
+}
+
+/* ================= LIST ================= */
+
+func (h *PeriodHandler) List(ctx *gin.Context) {
+	periods, err := h.periodService.List(ctx, ctx.GetInt("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get data periode berhasil",
+		"data":    periods,
+	})
+}
+
+/* ================= DETAIL ================= */
+
+func (h *PeriodHandler) GetByID(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	period, err := h.periodService.GetByID(ctx, ctx.GetInt("user_id"), id)
+	if err != nil {
+		ctx.JSON(periodErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get detail periode berhasil",
+		"data":    period,
+	})
+}
+
+func (h *PeriodHandler) GetDefault(ctx *gin.Context) {
+	period, err := h.periodService.GetDefault(ctx, ctx.GetInt("user_id"))
+	if err != nil {
+		ctx.JSON(periodErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get periode default berhasil",
+		"data":    period,
+	})
+}
+
+/* ================= UPDATE ================= */
+
+func (h *PeriodHandler) Update(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req dto.PeriodRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	period, err := h.periodService.Update(ctx, ctx.GetInt("user_id"), id, req)
+	if err != nil {
+		ctx.JSON(periodErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Periode berhasil diperbarui",
+		"data":    period,
+	})
+}
+
+func (h *PeriodHandler) SetDefault(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	period, err := h.periodService.SetDefault(ctx, ctx.GetInt("user_id"), id)
+	if err != nil {
+		ctx.JSON(periodErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Periode default berhasil diubah",
+		"data":    period,
+	})
+}
+
+/* ================= DELETE ================= */
+
+func (h *PeriodHandler) Delete(ctx *gin.Context) {
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.periodService.Delete(ctx, ctx.GetInt("user_id"), id); err != nil {
+		ctx.JSON(periodErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Periode berhasil dihapus",
+	})
+}
+
+func periodErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPeriodNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPeriodOverlap),
+		errors.Is(err, services.ErrPeriodIsDefault),
+		errors.Is(err, services.ErrPeriodHasTransactions):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	profileService services.ProfileService
+}
+
+func NewProfileHandler(profileService services.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+/* ================= MY PROFILE ================= */
+
+func (h *ProfileHandler) GetMe(ctx *gin.Context) {
+	profile, err := h.profileService.GetMyProfile(ctx, ctx.GetInt("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get profile berhasil",
+		"data":    profile,
+	})
+}
+
+func (h *ProfileHandler) UpdateMe(ctx *gin.Context) {
+	var req dto.ProfileRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.profileService.UpdateMyProfile(ctx, ctx.GetInt("user_id"), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Profile berhasil diperbarui",
+		"data":    profile,
+	})
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"mmgrapp/pkg/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseIDParam mengambil path param numerik, misal :id
+func parseIDParam(ctx *gin.Context, name string) (int, error) {
+	id, err := strconv.Atoi(ctx.Param(name))
+	if err != nil || id < 1 {
+		return 0, errors.New("invalid " + name)
+	}
+	return id, nil
+}
+
+// queryInt mengambil query param numerik, 0 jika kosong
+func queryInt(ctx *gin.Context, key string) (int, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New(key + " harus berupa angka")
+	}
+	return n, nil
+}
+
+// queryDate mengambil query param tanggal YYYY-MM-DD, nil jika kosong
+func queryDate(ctx *gin.Context, key string) (*time.Time, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := utils.ParseDate(value)
+	if err != nil {
+		return nil, errors.New(key + " harus berformat YYYY-MM-DD")
+	}
+	return &date, nil
+}
+
+// queryBool mengambil query param true/false, nil jika kosong
+func queryBool(ctx *gin.Context, key string) (*bool, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, errors.New(key + " harus berupa true / false")
+	}
+	return &b, nil
+}
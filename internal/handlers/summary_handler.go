@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SummaryHandler struct {
+	summaryService services.SummaryService
+}
+
+func NewSummaryHandler(summaryService services.SummaryService) *SummaryHandler {
+	return &SummaryHandler{summaryService: summaryService}
+}
+
+/* ================= SUMMARY ================= */
+
+func (h *SummaryHandler) GetSummary(ctx *gin.Context) {
+	var (
+		filter repositories.SummaryFilter
+		err    error
+	)
+
+	if filter.PeriodID, err = queryInt(ctx, "period_id"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.StartDate, err = queryDate(ctx, "start_date"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.EndDate, err = queryDate(ctx, "end_date"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	summary, err := h.summaryService.GetSummary(ctx, ctx.GetInt("user_id"), filter)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrPeriodNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Get ringkasan berhasil",
+		"data":    summary,
+	})
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"mmgrapp/internal/middlewares"
+	"mmgrapp/internal/models"
 	"mmgrapp/internal/services"
 	"net/http"
 	"strconv"
@@ -90,6 +92,9 @@ func (h *UserHandler) ResendOTP(ctx *gin.Context) {
 	// call service
 	err := h.userService.ResendOTP(ctx, req.Email, req.Purpose)
 	if err != nil {
+		if respondOTPCooldown(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -99,10 +104,62 @@ func (h *UserHandler) ResendOTP(ctx *gin.Context) {
 	})
 }
 
+/* ================= CHANGE EMAIL ================= */
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+func (h *UserHandler) RequestEmailChange(ctx *gin.Context) {
+	var req ChangeEmailRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userService.RequestEmailChange(ctx, ctx.GetInt("user_id"), req.NewEmail, req.Password); err != nil {
+		if respondLoginLockError(ctx, err) {
+			return
+		}
+		if respondOTPCooldown(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "OTP telah dikirim ke email baru",
+	})
+}
+
+type ConfirmEmailChangeRequest struct {
+	OTP string `json:"otp" binding:"required"`
+}
+
+func (h *UserHandler) ConfirmEmailChange(ctx *gin.Context) {
+	var req ConfirmEmailChangeRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userService.ConfirmEmailChange(ctx, ctx.GetInt("user_id"), req.OTP); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Email berhasil diubah",
+	})
+}
+
 func (h *UserHandler) MyDetail(ctx *gin.Context) {
 	// ambil user_id dari token
 	tokenUserID := ctx.GetInt("user_id")
-	isAdmin := ctx.GetBool("is_admin")
 
 	// ambil user_id dari param URL
 	paramID, err := strconv.Atoi(ctx.Param("id"))
@@ -112,7 +169,7 @@ func (h *UserHandler) MyDetail(ctx *gin.Context) {
 	}
 
 	// cek hak akses
-	if !isAdmin && paramID != tokenUserID {
+	if paramID != tokenUserID && !middlewares.HasPermission(ctx, models.PermUsersRead) {
 		ctx.JSON(http.StatusForbidden, gin.H{"error": "Tidak dapat mengakses data user ini"})
 		return
 	}
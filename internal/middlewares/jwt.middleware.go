@@ -1,15 +1,19 @@
 package middlewares
 
 import (
+	"errors"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/internal/services"
 	"mmgrapp/pkg/utils"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-// memeriksa token
-func JWTAuthMiddleware() gin.HandlerFunc {
+// memeriksa token dan status akun pemilik token di database
+func JWTAuthMiddleware(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
@@ -39,8 +43,60 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		ctx.Set("user_id", claims.UserID)
-		ctx.Set("is_admin", claims.IsAdmin)
+		// token yang sudah dicabut (logout / deaktivasi) ditolak walau belum expired
+		if claims.ID == "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			ctx.Abort()
+			return
+		}
+
+		revoked, err := utils.CurrentTokenDenylist().Contains(ctx, claims.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa token"})
+			ctx.Abort()
+			return
+		}
+		if revoked {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token sudah dicabut"})
+			ctx.Abort()
+			return
+		}
+
+		// status akun dicek ulang ke DB, token milik user nonaktif / terhapus ditolak
+		user, err := userRepo.FindByID(ctx, claims.UserID)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa akun"})
+				ctx.Abort()
+				return
+			}
+			user = nil
+		}
+
+		if err := services.CheckUserStatus(user); err != nil {
+			var statusErr *services.StatusError
+			errors.As(err, &statusErr)
+
+			status := http.StatusForbidden
+			if statusErr == services.ErrUserNotFound {
+				status = http.StatusUnauthorized
+			}
+			ctx.JSON(status, gin.H{"error": statusErr.Message, "code": statusErr.Code})
+			ctx.Abort()
+			return
+		}
+
+		// permission diambil dari DB, bukan dari claim token
+		permissions, err := roleRepo.FindPermissionNames(ctx, user.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa hak akses"})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set("user_id", user.ID)
+		ctx.Set("jti", claims.ID)
+		ctx.Set("permissions", permissions)
 
 		ctx.Next()
 	}
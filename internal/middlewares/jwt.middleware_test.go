@@ -0,0 +1,138 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/pkg/utils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// stubUserRepo hanya FindByID yang dipakai middleware
+type stubUserRepo struct {
+	repositories.UserRepository
+	users map[int]*models.User
+}
+
+func (r stubUserRepo) FindByID(ctx context.Context, id int) (*models.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type stubRoleRepo struct {
+	repositories.RoleRepository
+}
+
+func (stubRoleRepo) FindPermissionNames(ctx context.Context, userID int) ([]string, error) {
+	return nil, nil
+}
+
+func useTestJWTKey(t *testing.T) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, "test.pem"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := utils.CurrentSigner()
+	if err := utils.LoadJWTKeys(dir, "test"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { utils.SetSigner(previous) })
+}
+
+func TestJWTAuthMiddlewareRejectsDeniedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	useTestJWTKey(t)
+	utils.SetTokenDenylist(utils.NewMemoryDenylist())
+
+	users := stubUserRepo{users: map[int]*models.User{
+		1: {ID: 1, Username: "alice", IsActive: true, IsVerified: true},
+	}}
+
+	r := gin.New()
+	r.GET("/me", JWTAuthMiddleware(users, stubRoleRepo{}), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"jti": ctx.GetString("jti")})
+	})
+
+	token, jti, err := utils.GenerateAccessToken(1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jti == "" {
+		t.Fatal("access token tanpa jti")
+	}
+
+	request := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := request(); code != http.StatusOK {
+		t.Fatalf("token valid: status %d, want 200", code)
+	}
+
+	if err := utils.CurrentTokenDenylist().Add(context.Background(), jti, time.Now().Add(utils.AccessTokenTTL)); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := request(); code != http.StatusUnauthorized {
+		t.Fatalf("token di denylist: status %d, want 401", code)
+	}
+}
+
+func TestJWTAuthMiddlewareRejectsRefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	useTestJWTKey(t)
+
+	users := stubUserRepo{users: map[int]*models.User{
+		1: {ID: 1, Username: "alice", IsActive: true, IsVerified: true},
+	}}
+
+	r := gin.New()
+	r.GET("/me", JWTAuthMiddleware(users, stubRoleRepo{}), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	token, err := utils.GenerateRefreshTokenJWT(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("refresh token sebagai access token: status %d, want 401", w.Code)
+	}
+}
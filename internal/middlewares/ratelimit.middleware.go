@@ -0,0 +1,139 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"mmgrapp/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPeekBody batas body JSON yang dibaca untuk menentukan key rate limit
+const maxPeekBody = 1 << 20
+
+// RateLimitKeyFunc menentukan key bucket dari request, "" = aturan dilewati
+type RateLimitKeyFunc func(ctx *gin.Context) string
+
+// RateLimitRule satu aturan limit. Name jadi prefix key sehingga route yang
+// memakai Name sama berbagi bucket.
+type RateLimitRule struct {
+	Name string
+	Rate utils.Rate
+	Key  RateLimitKeyFunc
+}
+
+// RateLimit tolak request dengan 429 + Retry-After jika salah satu aturan habis.
+// Error backend tidak memblokir request (fail open) supaya limiter tidak jadi
+// titik gagal login / register.
+func RateLimit(rules ...RateLimitRule) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		for _, rule := range rules {
+			key := rule.Key(ctx)
+			if key == "" {
+				continue
+			}
+
+			allowed, retryAfter, err := utils.CurrentRateLimiter().Allow(ctx, rule.Name+":"+key, rule.Rate)
+			if err != nil {
+				log.Printf("❌ Rate limiter %s error: %v", rule.Name, err)
+				continue
+			}
+
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				ctx.Header("Retry-After", strconv.Itoa(seconds))
+				ctx.JSON(http.StatusTooManyRequests, gin.H{
+					"error":       fmt.Sprintf("terlalu banyak permintaan, coba lagi dalam %d detik", seconds),
+					"code":        "RATE_LIMITED",
+					"retry_after": seconds,
+				})
+				ctx.Abort()
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+// KeyByIP bucket per IP client
+func KeyByIP(ctx *gin.Context) string {
+	return ctx.ClientIP()
+}
+
+// KeyByJSONField bucket per nilai field body JSON (case-insensitive), misal
+// per email. Beberapa field digabung, dan aturan dilewati jika semuanya kosong.
+func KeyByJSONField(fields ...string) RateLimitKeyFunc {
+	return func(ctx *gin.Context) string {
+		body := peekJSONBody(ctx)
+
+		values := make([]string, 0, len(fields))
+		empty := true
+		for _, field := range fields {
+			value, _ := body[field].(string)
+			value = strings.ToLower(strings.TrimSpace(value))
+			if value != "" {
+				empty = false
+			}
+			values = append(values, value)
+		}
+
+		if empty {
+			return ""
+		}
+		return strings.Join(values, "|")
+	}
+}
+
+// KeyByEmailPurpose bucket per email (field emailField) + purpose OTP. Purpose
+// diambil dari body jika ada, selain itu pakai purpose bawaan route.
+func KeyByEmailPurpose(emailField, defaultPurpose string) RateLimitKeyFunc {
+	return func(ctx *gin.Context) string {
+		body := peekJSONBody(ctx)
+
+		email, _ := body[emailField].(string)
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" {
+			return ""
+		}
+
+		purpose, _ := body["purpose"].(string)
+		if purpose == "" {
+			purpose = defaultPurpose
+		}
+
+		return purpose + "|" + email
+	}
+}
+
+// peekJSONBody baca body JSON tanpa menghabiskannya untuk handler, hasil
+// parse disimpan di context supaya aturan berikutnya tidak membaca ulang
+func peekJSONBody(ctx *gin.Context) map[string]interface{} {
+	const cacheKey = "ratelimit_body"
+	if cached, ok := ctx.Get(cacheKey); ok {
+		return cached.(map[string]interface{})
+	}
+
+	body := map[string]interface{}{}
+	ctx.Set(cacheKey, body)
+
+	if ctx.Request.Body == nil {
+		return body
+	}
+
+	data, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxPeekBody))
+	if err != nil {
+		return body
+	}
+	ctx.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), ctx.Request.Body))
+
+	_ = json.Unmarshal(data, &body)
+	return body
+}
@@ -0,0 +1,82 @@
+package middlewares
+
+import (
+	config "mmgrapp/internal/configs"
+	"mmgrapp/pkg/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestEngine engine dengan pengaturan proxy yang sama seperti cmd/server
+func newTestEngine(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRateLimitByIPIgnoresSpoofedForwardedFor(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "")
+	utils.SetRateLimiter(utils.NewMemoryRateLimiter())
+
+	r := newTestEngine(t)
+	r.POST("/otp", RateLimit(RateLimitRule{
+		Name: "otp-ip",
+		Rate: utils.Rate{Limit: 2, Period: time.Minute},
+		Key:  KeyByIP,
+	}), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	codes := make([]int, 0, 3)
+	for _, forwarded := range []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"} {
+		req := httptest.NewRequest(http.MethodPost, "/otp", nil)
+		req.RemoteAddr = "203.0.113.7:40000"
+		req.Header.Set("X-Forwarded-For", forwarded)
+		req.Header.Set("X-Real-IP", forwarded)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+
+	// ketiga request satu bucket (IP koneksi), request ke-3 melewati batas
+	if codes[0] != http.StatusOK || codes[1] != http.StatusOK || codes[2] != http.StatusTooManyRequests {
+		t.Fatalf("status = %v, want [200 200 429]", codes)
+	}
+}
+
+func TestKeyByIPUsesForwardedForFromTrustedProxy(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8")
+
+	r := newTestEngine(t)
+	r.GET("/ip", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, KeyByIP(ctx))
+	})
+
+	tests := []struct {
+		remoteAddr string
+		want       string
+	}{
+		{"10.1.2.3:40000", "198.51.100.9"},   // lewat proxy terpercaya
+		{"203.0.113.7:40000", "203.0.113.7"}, // langsung dari client, header diabaikan
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = tt.remoteAddr
+		req.Header.Set("X-Forwarded-For", "198.51.100.9")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if got := w.Body.String(); got != tt.want {
+			t.Errorf("remote %s: key = %q, want %q", tt.remoteAddr, got, tt.want)
+		}
+	}
+}
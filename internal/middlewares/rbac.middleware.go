@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission hanya meneruskan request jika user punya semua permission,
+// dipasang setelah JWTAuthMiddleware
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		for _, permission := range permissions {
+			if !HasPermission(ctx, permission) {
+				ctx.JSON(http.StatusForbidden, gin.H{
+					"error": "Tidak memiliki akses",
+					"code":  "PERMISSION_DENIED",
+				})
+				ctx.Abort()
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+// HasPermission cek permission user yang sudah di-set JWTAuthMiddleware
+func HasPermission(ctx *gin.Context, permission string) bool {
+	return slices.Contains(ctx.GetStringSlice("permissions"), permission)
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Status email di outbox
+const (
+	EmailStatusPending = "pending" // menunggu dikirim / dicoba ulang
+	EmailStatusSending = "sending" // sedang diproses worker
+	EmailStatusSent    = "sent"
+	EmailStatusDead    = "dead" // gagal terus sampai batas percobaan, perlu dicek admin
+)
+
+// EmailOutbox email yang antre dikirim worker. Disimpan dalam transaksi yang
+// sama dengan data pemicunya (mis. OTP) supaya email tidak hilang / nyasar.
+type EmailOutbox struct {
+	ID       int    `gorm:"primaryKey" json:"id"`
+	ToEmail  string `gorm:"size:100;not null;index" json:"to_email"`
+	Subject  string `gorm:"size:255" json:"subject"`
+	Template string `gorm:"size:50" json:"template"` // nama template, untuk filter & debugging
+
+	// isi email bisa berisi OTP, tidak pernah ditampilkan di API. Dikosongkan
+	// setelah terkirim, juga saat email ber-ExpiresAt masuk dead.
+	TextBody string `json:"-"`
+	HTMLBody string `json:"-"`
+
+	// lewat dari ini email tidak dikirim lagi tapi langsung dead (mis. OTP kadaluarsa)
+	ExpiresAt *time.Time `gorm:"index" json:"expires_at"`
+
+	Status        string     `gorm:"size:20;index;default:pending" json:"status"`
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastError     string     `gorm:"size:1000" json:"last_error"`
+	SentAt        *time.Time `json:"sent_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -5,8 +5,16 @@ import "time"
 type RefreshToken struct {
 	ID        int       `gorm:"primaryKey"`
 	UserID    int       `gorm:"index;not null"`  // index untuk query cepat
-	Token     string    `gorm:"unique;not null"` // pastikan token unik
+	Token     string    `gorm:"unique;not null"` // SHA-256 digest token, unik
+	FamilyID  string    `gorm:"index;size:64"`   // rantai rotasi dari satu login
 	ExpiresAt time.Time `gorm:"not null"`        // expiry token
 	IsRevoked bool      `gorm:"default:false"`   // untuk revoke / logout
-	CreatedAt time.Time `gorm:"autoCreateTime"`  // timestamp otomatis
+	AccessJTI string    `gorm:"index;size:64"`   // jti access token yang terbit bersama token ini
+
+	// info perangkat untuk daftar sesi
+	UserAgent   string `gorm:"size:512"`
+	IPAddress   string `gorm:"size:64"`
+	DeviceLabel string `gorm:"size:100"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"` // timestamp otomatis
 }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Prefix key LoginAttempt, satu baris per user dan per IP
+const (
+	LoginAttemptKeyUser = "user:"
+	LoginAttemptKeyIP   = "ip:"
+)
+
+// LoginAttempt penghitung gagal login untuk satu key (user / IP)
+type LoginAttempt struct {
+	ID           int        `gorm:"primaryKey"`
+	Key          string     `gorm:"column:attempt_key;uniqueIndex;size:128;not null"` // contoh: "user:12" atau "ip:10.0.0.1"
+	FailedCount  int        `gorm:"default:0"`
+	LastFailedAt time.Time  // acuan jeda bertahap & reset window
+	LockedUntil  *time.Time // terisi saat key sedang dikunci
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// UserMFA secret TOTP milik user. EnabledAt nil berarti enrollment belum
+// dikonfirmasi dengan kode pertama.
+type UserMFA struct {
+	ID           int        `gorm:"primaryKey"`
+	UserID       int        `gorm:"uniqueIndex;not null"`
+	Secret       string     `gorm:"size:64;not null" json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep int64      `json:"-"` // langkah TOTP terakhir yang dipakai, cegah replay kode
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// MFARecoveryCode kode cadangan sekali pakai, disimpan sebagai digest SHA-256
+type MFARecoveryCode struct {
+	ID       int        `gorm:"primaryKey"`
+	UserID   int        `gorm:"index;not null"`
+	CodeHash string     `gorm:"uniqueIndex;size:64;not null"`
+	UsedAt   *time.Time // terisi setelah dipakai login
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
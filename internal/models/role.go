@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+type Role struct {
+	ID          int          `gorm:"primaryKey" json:"id"`
+	Name        string       `gorm:"uniqueIndex;size:50" json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Permission struct {
+	ID          int    `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"uniqueIndex;size:100" json:"name"`
+	Description string `json:"description"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// role bawaan
+const (
+	RoleSuperAdmin = "superadmin"
+	RoleSupport    = "support"
+	RoleAuditor    = "auditor"
+)
+
+// permission bawaan
+const (
+	PermUsersRead   = "users.read"   // lihat & cari user
+	PermUsersManage = "users.manage" // ubah status, verifikasi, reset password user
+	PermUsersDelete = "users.delete" // hapus user
+	PermAuditRead   = "audit.read"   // lihat data audit / aktivitas sistem
+
+	PermEmailsRead   = "emails.read"   // lihat status pengiriman email (outbox)
+	PermEmailsManage = "emails.manage" // kirim ulang email yang gagal
+)
+
+// DefaultPermissions deskripsi permission bawaan untuk seeding
+var DefaultPermissions = map[string]string{
+	PermUsersRead:   "Melihat dan mencari data user",
+	PermUsersManage: "Mengubah status, verifikasi dan reset password user",
+	PermUsersDelete: "Menghapus user",
+	PermAuditRead:   "Melihat data audit sistem",
+
+	PermEmailsRead:   "Melihat status pengiriman email",
+	PermEmailsManage: "Mengirim ulang email yang gagal terkirim",
+}
+
+// DefaultRoles role bawaan beserta permission-nya untuk seeding
+var DefaultRoles = map[string][]string{
+	RoleSuperAdmin: {PermUsersRead, PermUsersManage, PermUsersDelete, PermAuditRead, PermEmailsRead, PermEmailsManage},
+	RoleSupport:    {PermUsersRead, PermEmailsRead},
+	RoleAuditor:    {PermUsersRead, PermAuditRead},
+}
@@ -12,7 +12,6 @@ type User struct {
 	Email      string `gorm:"uniqueIndex;size:100" json:"email"`
 	Password   string `json:"-"` // disembunyikan dari response JSON
 	IsActive   bool   `gorm:"default:true" json:"is_active"`
-	IsAdmin    bool   `gorm:"default:false" json:"is_admin"`
 	IsVerified bool   `gorm:"default:false" json:"is_verified"`
 
 	CreatedAt time.Time      `json:"created_at"`
@@ -25,6 +24,9 @@ type User struct {
 
 	// Relasi ke Profile
 	Profile *Profile `gorm:"foreignKey:UserID;references:ID" json:"profile,omitempty"`
+
+	// Relasi ke Role (RBAC)
+	Roles []Role `gorm:"many2many:user_roles" json:"roles,omitempty"`
 }
 
 type Profile struct {
@@ -33,7 +35,8 @@ type Profile struct {
 	MiddleName string `json:"middle_name"`
 	LastName   string `json:"last_name"`
 	FullName   string `json:"full_name"`
-	UserID     int    `json:"user_id"`
+	Locale     string `gorm:"size:5;default:id" json:"locale"` // bahasa email & notifikasi: id / en
+	UserID     int    `gorm:"uniqueIndex" json:"user_id"`
 	User       *User  `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
 
 	CreatedAt time.Time      `json:"created_at"`
@@ -53,7 +56,7 @@ type Period struct {
 
 	StartDate time.Time      `json:"start_date"`
 	EndDate   time.Time      `json:"end_date"`
-	IsDefault bool           `gorm:"default:true" json:"is_default"`
+	IsDefault bool           `gorm:"default:false" json:"is_default"` // hanya satu periode default per user
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
@@ -82,11 +85,21 @@ type Account struct {
 	DeletedBy *int `json:"deleted_by,omitempty"`
 }
 
+// jenis akun yang diperbolehkan
+const (
+	AccountTypeCash    = "cash"
+	AccountTypeBank    = "bank"
+	AccountTypeEWallet = "e_wallet"
+	AccountTypeOther   = "other"
+)
+
 type UserOTP struct {
 	ID        int `gorm:"primaryKey"`
 	UserID    int `gorm:"index"`
 	OTP       string
 	ExpiresAt time.Time
-	Purpose   string // e.g. "email_verification", "reset_password"
+	Purpose   string // lihat services.OTPPurpose*, mis. "email_verification", "password_reset"
+	Target    string `gorm:"size:100"`  // tujuan OTP jika bukan email user, mis. email baru untuk email_change
+	Attempts  int    `gorm:"default:0"` // jumlah percobaan verifikasi
 	CreatedAt time.Time
 }
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"mmgrapp/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AccountRepository interface {
+	Create(ctx context.Context, account *models.Account) error
+	FindAll(ctx context.Context, userID int, includeInactive bool) ([]models.Account, error)
+	FindByID(ctx context.Context, id, userID int) (*models.Account, error)
+	Update(ctx context.Context, account *models.Account) error
+	SetActive(ctx context.Context, id, userID int, isActive bool, updatedBy int) error
+	CountTransactions(ctx context.Context, id int) (int64, error)
+	Delete(ctx context.Context, id, userID, deletedBy int) error
+}
+
+type accountRepo struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return &accountRepo{db: db}
+}
+
+func (r *accountRepo) Create(ctx context.Context, account *models.Account) error {
+	return conn(ctx, r.db).Create(account).Error
+}
+
+func (r *accountRepo) FindAll(ctx context.Context, userID int, includeInactive bool) ([]models.Account, error) {
+	var accounts []models.Account
+
+	query := conn(ctx, r.db).Where("user_id = ?", userID)
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	if err := query.Order("name ASC").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+func (r *accountRepo) FindByID(ctx context.Context, id, userID int) (*models.Account, error) {
+	var account models.Account
+	err := conn(ctx, r.db).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (r *accountRepo) Update(ctx context.Context, account *models.Account) error {
+	return conn(ctx, r.db).Save(account).Error
+}
+
+// SetActive archive / aktifkan kembali akun
+func (r *accountRepo) SetActive(ctx context.Context, id, userID int, isActive bool, updatedBy int) error {
+	result := conn(ctx, r.db).
+		Model(&models.Account{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{
+			"is_active":  isActive,
+			"updated_by": updatedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// CountTransactions menghitung pemasukan + pengeluaran yang masih memakai akun
+func (r *accountRepo) CountTransactions(ctx context.Context, id int) (int64, error) {
+	var incomes, expenses int64
+
+	if err := conn(ctx, r.db).Model(&models.Income{}).Where("account_id = ?", id).Count(&incomes).Error; err != nil {
+		return 0, err
+	}
+	if err := conn(ctx, r.db).Model(&models.Expense{}).Where("account_id = ?", id).Count(&expenses).Error; err != nil {
+		return 0, err
+	}
+
+	return incomes + expenses, nil
+}
+
+// Delete soft delete akun sekaligus mengisi deleted_by
+func (r *accountRepo) Delete(ctx context.Context, id, userID, deletedBy int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Account{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Update("deleted_by", deletedBy)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Account{}).Error
+	})
+}
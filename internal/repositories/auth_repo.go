@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"mmgrapp/internal/models"
+	"mmgrapp/pkg/utils"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,6 +15,15 @@ type AuthRepository interface {
 	UpdatePassword(ctx context.Context, user *models.User) error
 	FindRefreshTokenByToken(ctx context.Context, token string) (*models.RefreshToken, error)
 	RevokeRefreshToken(ctx context.Context, token string) error
+	RevokeRefreshTokenByID(ctx context.Context, id int) error
+	RevokeAllRefreshTokens(ctx context.Context, userID int) error
+	RevokeTokenFamily(ctx context.Context, familyID string) error
+	FindActiveSessions(ctx context.Context, userID int) ([]models.RefreshToken, error)
+	FindRefreshTokenByID(ctx context.Context, id, userID int) (*models.RefreshToken, error)
+	FindTokensIssuedSince(ctx context.Context, userID int, since time.Time) ([]models.RefreshToken, error)
+	FindRefreshTokenByAccessJTI(ctx context.Context, userID int, jti string) (*models.RefreshToken, error)
+	RevokeOtherRefreshTokens(ctx context.Context, userID int, keepFamilyID string) error
+	FindFamilyTokensIssuedSince(ctx context.Context, familyID string, since time.Time) ([]models.RefreshToken, error)
 }
 
 type authRepo struct {
@@ -24,18 +34,22 @@ func NewAuthRepository(db *gorm.DB) AuthRepository {
 	return &authRepo{db}
 }
 
+// CreateRefreshToken menyimpan refresh token sebagai digest SHA-256, bukan plaintext
 func (r *authRepo) CreateRefreshToken(ctx context.Context, refreshToken *models.RefreshToken) error {
-	return r.db.WithContext(ctx).Create(refreshToken).Error
+	refreshToken.Token = utils.HashToken(refreshToken.Token)
+	return conn(ctx, r.db).Create(refreshToken).Error
 }
 
 func (r *authRepo) UpdatePassword(ctx context.Context, user *models.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return conn(ctx, r.db).Save(user).Error
 }
 
+// FindRefreshTokenByToken ambil token apa adanya (termasuk yang sudah revoke / expired),
+// pengecekan status dilakukan di service untuk deteksi reuse
 func (r *authRepo) FindRefreshTokenByToken(ctx context.Context, token string) (*models.RefreshToken, error) {
 	var rt models.RefreshToken
-	err := r.db.WithContext(ctx).
-		Where("token = ? AND is_revoked = false AND expires_at > ?", token, time.Now()).
+	err := conn(ctx, r.db).
+		Where("token = ?", utils.HashToken(token)).
 		First(&rt).Error
 
 	if err != nil {
@@ -48,10 +62,11 @@ func (r *authRepo) FindRefreshTokenByToken(ctx context.Context, token string) (*
 	return &rt, nil
 }
 
+// RevokeRefreshToken revoke token yang masih aktif, error jika sudah lebih dulu di-revoke
 func (r *authRepo) RevokeRefreshToken(ctx context.Context, token string) error {
-	result := r.db.WithContext(ctx).
+	result := conn(ctx, r.db).
 		Model(&models.RefreshToken{}).
-		Where("token = ?", token).
+		Where("token = ? AND is_revoked = false", utils.HashToken(token)).
 		Update("is_revoked", true)
 
 	if result.Error != nil {
@@ -64,3 +79,110 @@ func (r *authRepo) RevokeRefreshToken(ctx context.Context, token string) error {
 
 	return nil
 }
+
+func (r *authRepo) RevokeRefreshTokenByID(ctx context.Context, id int) error {
+	return conn(ctx, r.db).
+		Model(&models.RefreshToken{}).
+		Where("id = ?", id).
+		Update("is_revoked", true).Error
+}
+
+// RevokeAllRefreshTokens revoke semua refresh token aktif milik user
+func (r *authRepo) RevokeAllRefreshTokens(ctx context.Context, userID int) error {
+	return conn(ctx, r.db).
+		Model(&models.RefreshToken{}).
+		Where("user_id = ? AND is_revoked = false", userID).
+		Update("is_revoked", true).Error
+}
+
+// RevokeOtherRefreshTokens revoke semua token aktif user kecuali family keepFamilyID
+func (r *authRepo) RevokeOtherRefreshTokens(ctx context.Context, userID int, keepFamilyID string) error {
+	return conn(ctx, r.db).
+		Model(&models.RefreshToken{}).
+		Where("user_id = ? AND family_id <> ? AND is_revoked = false", userID, keepFamilyID).
+		Update("is_revoked", true).Error
+}
+
+// RevokeTokenFamily revoke semua token dalam satu family rotasi
+func (r *authRepo) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	return conn(ctx, r.db).
+		Model(&models.RefreshToken{}).
+		Where("family_id = ? AND is_revoked = false", familyID).
+		Update("is_revoked", true).Error
+}
+
+// FindActiveSessions token aktif terakhir dari tiap sesi (family) milik user.
+// Token lama tanpa family dianggap sesi tersendiri.
+func (r *authRepo) FindActiveSessions(ctx context.Context, userID int) ([]models.RefreshToken, error) {
+	db := conn(ctx, r.db)
+	now := time.Now()
+
+	latestPerFamily := db.Model(&models.RefreshToken{}).
+		Select("MAX(id)").
+		Where("user_id = ? AND is_revoked = false AND expires_at > ? AND family_id <> ''", userID, now).
+		Group("family_id")
+
+	var tokens []models.RefreshToken
+	err := db.
+		Where("user_id = ? AND is_revoked = false AND expires_at > ?", userID, now).
+		Where("family_id = '' OR family_id IS NULL OR id IN (?)", latestPerFamily).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *authRepo) FindRefreshTokenByID(ctx context.Context, id, userID int) (*models.RefreshToken, error) {
+	var rt models.RefreshToken
+	err := conn(ctx, r.db).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&rt).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &rt, nil
+}
+
+// FindTokensIssuedSince token user yang terbit setelah since, dipakai untuk mencari
+// access token (jti) yang mungkin masih berlaku
+func (r *authRepo) FindTokensIssuedSince(ctx context.Context, userID int, since time.Time) ([]models.RefreshToken, error) {
+	var tokens []models.RefreshToken
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *authRepo) FindFamilyTokensIssuedSince(ctx context.Context, familyID string, since time.Time) ([]models.RefreshToken, error) {
+	var tokens []models.RefreshToken
+	err := conn(ctx, r.db).
+		Where("family_id = ? AND created_at > ?", familyID, since).
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// FindRefreshTokenByAccessJTI refresh token yang terbit bersama access token jti,
+// dipakai untuk mengenali sesi yang sedang dipakai request
+func (r *authRepo) FindRefreshTokenByAccessJTI(ctx context.Context, userID int, jti string) (*models.RefreshToken, error) {
+	var rt models.RefreshToken
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND access_jti = ?", userID, jti).
+		First(&rt).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &rt, nil
+}
@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+	"mmgrapp/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EmailOutboxFilter filter & pagination untuk list outbox (admin)
+type EmailOutboxFilter struct {
+	Status  string
+	ToEmail string
+	Page    int
+	Limit   int
+}
+
+type EmailOutboxRepository interface {
+	Create(ctx context.Context, email *models.EmailOutbox) error
+	ClaimDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]models.EmailOutbox, error)
+	MarkSent(ctx context.Context, id int) error
+	MarkFailed(ctx context.Context, id int, nextAttemptAt time.Time, lastError string, dead bool) error
+	FindAll(ctx context.Context, filter EmailOutboxFilter) ([]models.EmailOutbox, int64, error)
+	FindByID(ctx context.Context, id int) (*models.EmailOutbox, error)
+	Requeue(ctx context.Context, id int) error
+}
+
+type emailOutboxRepo struct {
+	db *gorm.DB
+}
+
+func NewEmailOutboxRepository(db *gorm.DB) EmailOutboxRepository {
+	return &emailOutboxRepo{db: db}
+}
+
+func (r *emailOutboxRepo) Create(ctx context.Context, email *models.EmailOutbox) error {
+	return conn(ctx, r.db).Create(email).Error
+}
+
+// ClaimDue ambil email yang sudah waktunya dikirim lalu tandai "sending".
+// Email "sending" yang macet sejak staleBefore (worker mati di tengah jalan)
+// ikut diambil ulang. Klaim per baris bersyarat sehingga aman untuk beberapa worker.
+func (r *emailOutboxRepo) ClaimDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]models.EmailOutbox, error) {
+	db := conn(ctx, r.db)
+	due := func(query *gorm.DB) *gorm.DB {
+		return query.Where(
+			"(status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at < ?)",
+			models.EmailStatusPending, now, models.EmailStatusSending, staleBefore,
+		)
+	}
+
+	var candidates []models.EmailOutbox
+	err := due(db.Model(&models.EmailOutbox{})).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]models.EmailOutbox, 0, len(candidates))
+	for _, email := range candidates {
+		result := due(db.Model(&models.EmailOutbox{}).Where("id = ?", email.ID)).
+			Updates(map[string]interface{}{
+				"status":     models.EmailStatusSending,
+				"attempts":   gorm.Expr("attempts + 1"),
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return claimed, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue // sudah diklaim worker lain
+		}
+
+		email.Status = models.EmailStatusSending
+		email.Attempts++
+		claimed = append(claimed, email)
+	}
+
+	return claimed, nil
+}
+
+func (r *emailOutboxRepo) MarkSent(ctx context.Context, id int) error {
+	now := time.Now()
+	return conn(ctx, r.db).
+		Model(&models.EmailOutbox{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.EmailStatusSent,
+			"sent_at":    now,
+			"last_error": "",
+			"text_body":  "",
+			"html_body":  "",
+		}).Error
+}
+
+// MarkFailed jadwalkan percobaan berikutnya, atau pindah ke dead letter jika dead.
+// Isi email ber-ExpiresAt (OTP) dibuang saat dead karena tidak bisa dikirim ulang.
+func (r *emailOutboxRepo) MarkFailed(ctx context.Context, id int, nextAttemptAt time.Time, lastError string, dead bool) error {
+	status := models.EmailStatusPending
+	if dead {
+		status = models.EmailStatusDead
+	}
+
+	if len(lastError) > 1000 {
+		lastError = lastError[:1000]
+	}
+
+	fields := map[string]interface{}{
+		"status":          status,
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastError,
+	}
+	if dead {
+		fields["text_body"] = gorm.Expr("CASE WHEN expires_at IS NULL THEN text_body ELSE '' END")
+		fields["html_body"] = gorm.Expr("CASE WHEN expires_at IS NULL THEN html_body ELSE '' END")
+	}
+
+	return conn(ctx, r.db).
+		Model(&models.EmailOutbox{}).
+		Where("id = ?", id).
+		Updates(fields).Error
+}
+
+func (r *emailOutboxRepo) FindAll(ctx context.Context, filter EmailOutboxFilter) ([]models.EmailOutbox, int64, error) {
+	var (
+		emails []models.EmailOutbox
+		total  int64
+	)
+
+	query := conn(ctx, r.db).Model(&models.EmailOutbox{})
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ToEmail != "" {
+		query = query.Where(`LOWER(to_email) LIKE ? ESCAPE '\'`, likeContains(strings.ToLower(filter.ToEmail)))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("id DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&emails).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return emails, total, nil
+}
+
+func (r *emailOutboxRepo) FindByID(ctx context.Context, id int) (*models.EmailOutbox, error) {
+	var email models.EmailOutbox
+	if err := conn(ctx, r.db).First(&email, id).Error; err != nil {
+		return nil, err
+	}
+	return &email, nil
+}
+
+// Requeue kirim ulang email dead secepatnya dengan hitungan percobaan dari nol,
+// ErrRecordNotFound jika email tidak ada, bukan dead atau sudah kadaluarsa
+func (r *emailOutboxRepo) Requeue(ctx context.Context, id int) error {
+	now := time.Now()
+	result := conn(ctx, r.db).
+		Model(&models.EmailOutbox{}).
+		Where("id = ? AND status = ?", id, models.EmailStatusDead).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Updates(map[string]interface{}{
+			"status":          models.EmailStatusPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -1 +1,113 @@
 package repositories
+
+import (
+	"context"
+	"mmgrapp/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExpenseFilter filter & pagination untuk list pengeluaran
+type ExpenseFilter struct {
+	PeriodID  int
+	AccountID int
+	Category  string
+	StartDate *time.Time
+	EndDate   *time.Time
+	Page      int
+	Limit     int
+}
+
+type ExpenseRepository interface {
+	Create(ctx context.Context, expense *models.Expense) error
+	FindAll(ctx context.Context, userID int, filter ExpenseFilter) ([]models.Expense, int64, error)
+	FindByID(ctx context.Context, id, userID int) (*models.Expense, error)
+	Update(ctx context.Context, expense *models.Expense) error
+	Delete(ctx context.Context, id, userID, deletedBy int) error
+}
+
+type expenseRepo struct {
+	db *gorm.DB
+}
+
+func NewExpenseRepository(db *gorm.DB) ExpenseRepository {
+	return &expenseRepo{db: db}
+}
+
+func (r *expenseRepo) Create(ctx context.Context, expense *models.Expense) error {
+	return conn(ctx, r.db).Create(expense).Error
+}
+
+func (r *expenseRepo) FindAll(ctx context.Context, userID int, filter ExpenseFilter) ([]models.Expense, int64, error) {
+	var (
+		expenses []models.Expense
+		total    int64
+	)
+
+	query := conn(ctx, r.db).Model(&models.Expense{}).Where("user_id = ?", userID)
+
+	if filter.PeriodID != 0 {
+		query = query.Where("period_id = ?", filter.PeriodID)
+	}
+	if filter.AccountID != 0 {
+		query = query.Where("account_id = ?", filter.AccountID)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("date <= ?", *filter.EndDate)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("date DESC, id DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&expenses).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return expenses, total, nil
+}
+
+func (r *expenseRepo) FindByID(ctx context.Context, id, userID int) (*models.Expense, error) {
+	var expense models.Expense
+	err := conn(ctx, r.db).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&expense).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &expense, nil
+}
+
+func (r *expenseRepo) Update(ctx context.Context, expense *models.Expense) error {
+	return conn(ctx, r.db).Save(expense).Error
+}
+
+// Delete soft delete pengeluaran sekaligus mengisi deleted_by
+func (r *expenseRepo) Delete(ctx context.Context, id, userID, deletedBy int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Expense{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Update("deleted_by", deletedBy)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Expense{}).Error
+	})
+}
@@ -1 +1,113 @@
 package repositories
+
+import (
+	"context"
+	"mmgrapp/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IncomeFilter filter & pagination untuk list pemasukan
+type IncomeFilter struct {
+	PeriodID  int
+	AccountID int
+	Category  string
+	StartDate *time.Time
+	EndDate   *time.Time
+	Page      int
+	Limit     int
+}
+
+type IncomeRepository interface {
+	Create(ctx context.Context, income *models.Income) error
+	FindAll(ctx context.Context, userID int, filter IncomeFilter) ([]models.Income, int64, error)
+	FindByID(ctx context.Context, id, userID int) (*models.Income, error)
+	Update(ctx context.Context, income *models.Income) error
+	Delete(ctx context.Context, id, userID, deletedBy int) error
+}
+
+type incomeRepo struct {
+	db *gorm.DB
+}
+
+func NewIncomeRepository(db *gorm.DB) IncomeRepository {
+	return &incomeRepo{db: db}
+}
+
+func (r *incomeRepo) Create(ctx context.Context, income *models.Income) error {
+	return conn(ctx, r.db).Create(income).Error
+}
+
+func (r *incomeRepo) FindAll(ctx context.Context, userID int, filter IncomeFilter) ([]models.Income, int64, error) {
+	var (
+		incomes []models.Income
+		total   int64
+	)
+
+	query := conn(ctx, r.db).Model(&models.Income{}).Where("user_id = ?", userID)
+
+	if filter.PeriodID != 0 {
+		query = query.Where("period_id = ?", filter.PeriodID)
+	}
+	if filter.AccountID != 0 {
+		query = query.Where("account_id = ?", filter.AccountID)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("date <= ?", *filter.EndDate)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("date DESC, id DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&incomes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return incomes, total, nil
+}
+
+func (r *incomeRepo) FindByID(ctx context.Context, id, userID int) (*models.Income, error) {
+	var income models.Income
+	err := conn(ctx, r.db).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&income).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &income, nil
+}
+
+func (r *incomeRepo) Update(ctx context.Context, income *models.Income) error {
+	return conn(ctx, r.db).Save(income).Error
+}
+
+// Delete soft delete pemasukan sekaligus mengisi deleted_by
+func (r *incomeRepo) Delete(ctx context.Context, id, userID, deletedBy int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Income{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Update("deleted_by", deletedBy)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Income{}).Error
+	})
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mmgrapp/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepository interface {
+	FindByKey(ctx context.Context, key string) (*models.LoginAttempt, error)
+	RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*models.LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+type loginAttemptRepo struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepo{db: db}
+}
+
+func (r *loginAttemptRepo) FindByKey(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	if err := conn(ctx, r.db).Where("attempt_key = ?", key).First(&attempt).Error; err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// RegisterFailure tambah hitungan gagal login dalam satu transaksi. Hitungan
+// dimulai ulang jika gagal terakhir sudah lewat dari window.
+func (r *loginAttemptRepo) RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("attempt_key = ?", key).First(&attempt).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			attempt = models.LoginAttempt{Key: key}
+		} else if now.Sub(attempt.LastFailedAt) > window {
+			attempt.FailedCount = 0
+		}
+
+		attempt.FailedCount++
+		attempt.LastFailedAt = now
+		return tx.Save(&attempt).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+func (r *loginAttemptRepo) Lock(ctx context.Context, key string, until time.Time) error {
+	return conn(ctx, r.db).
+		Model(&models.LoginAttempt{}).
+		Where("attempt_key = ?", key).
+		Update("locked_until", until).Error
+}
+
+// Reset hapus hitungan & kunci (login berhasil / unlock)
+func (r *loginAttemptRepo) Reset(ctx context.Context, key string) error {
+	return conn(ctx, r.db).Where("attempt_key = ?", key).Delete(&models.LoginAttempt{}).Error
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"mmgrapp/internal/models"
+	"mmgrapp/pkg/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MFARepository interface {
+	FindByUserID(ctx context.Context, userID int) (*models.UserMFA, error)
+	SavePending(ctx context.Context, userID int, secret string) error
+	Enable(ctx context.Context, userID int, step int64) error
+	Delete(ctx context.Context, userID int) error
+	MarkStepUsed(ctx context.Context, userID int, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID int, codes []string) error
+	UseRecoveryCode(ctx context.Context, userID int, code string) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID int) (int64, error)
+}
+
+type mfaRepo struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepo{db: db}
+}
+
+func (r *mfaRepo) FindByUserID(ctx context.Context, userID int) (*models.UserMFA, error) {
+	var mfa models.UserMFA
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).First(&mfa).Error; err != nil {
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+// SavePending simpan / ganti secret yang belum diaktifkan
+func (r *mfaRepo) SavePending(ctx context.Context, userID int, secret string) error {
+	return conn(ctx, r.db).
+		Where(models.UserMFA{UserID: userID}).
+		Assign(map[string]interface{}{
+			"secret":         secret,
+			"enabled_at":     nil,
+			"last_used_step": 0,
+		}).
+		FirstOrCreate(&models.UserMFA{}).Error
+}
+
+// Enable aktifkan MFA, step kode konfirmasi dicatat supaya tidak bisa dipakai login
+func (r *mfaRepo) Enable(ctx context.Context, userID int, step int64) error {
+	result := conn(ctx, r.db).
+		Model(&models.UserMFA{}).
+		Where("user_id = ? AND enabled_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"enabled_at":     time.Now(),
+			"last_used_step": step,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete matikan MFA beserta semua recovery code
+func (r *mfaRepo) Delete(ctx context.Context, userID int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserMFA{}).Error
+	})
+}
+
+// MarkStepUsed catat step TOTP secara atomik, false jika step tsb (atau yang
+// lebih baru) sudah pernah dipakai
+func (r *mfaRepo) MarkStepUsed(ctx context.Context, userID int, step int64) (bool, error) {
+	result := conn(ctx, r.db).
+		Model(&models.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReplaceRecoveryCodes hapus code lama lalu simpan digest code baru
+func (r *mfaRepo) ReplaceRecoveryCodes(ctx context.Context, userID int, codes []string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		rows := make([]models.MFARecoveryCode, 0, len(codes))
+		for _, code := range codes {
+			rows = append(rows, models.MFARecoveryCode{UserID: userID, CodeHash: utils.HashToken(code)})
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// UseRecoveryCode tandai code terpakai, false jika code salah / sudah dipakai
+func (r *mfaRepo) UseRecoveryCode(ctx context.Context, userID int, code string) (bool, error) {
+	result := conn(ctx, r.db).
+		Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, utils.HashToken(code)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *mfaRepo) CountUnusedRecoveryCodes(ctx context.Context, userID int) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
@@ -9,10 +9,11 @@ import (
 )
 
 type OTPRepository interface {
-	Create(ctx context.Context, otp *models.UserOTP) error
 	FindValidOTP(ctx context.Context, userID int, purpose string) (*models.UserOTP, error)
-	DeleteOTP(ctx context.Context, userID int, purpose string) error
-	UpdateOTP(ctx context.Context, userID int, purpose string, hashedOTP string, otpExpires_time time.Time) error
+	FindLatest(ctx context.Context, userID int, purpose string) (*models.UserOTP, error)
+	RegisterAttempt(ctx context.Context, id int, maxAttempts int) (bool, error)
+	Consume(ctx context.Context, id int) error
+	Replace(ctx context.Context, otp *models.UserOTP, email *models.EmailOutbox) error
 }
 
 type otpRepo struct {
@@ -23,18 +24,29 @@ func NewOTPRepository(db *gorm.DB) OTPRepository {
 	return &otpRepo{db: db}
 }
 
-func (r *otpRepo) Create(ctx context.Context, otp *models.UserOTP) error {
-	return r.db.Create(otp).Error
-}
-
 func (r *otpRepo) FindValidOTP(ctx context.Context, userID int, purpose string) (*models.UserOTP, error) {
 	var otp models.UserOTP
 
-	err := r.db.Where(
+	err := conn(ctx, r.db).Where(
 		"user_id = ? AND purpose = ? AND expires_at > ?",
 		userID, purpose, time.Now(),
-	).First(&otp).Error
+	).Order("id DESC").First(&otp).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &otp, nil
+}
 
+// FindLatest OTP terakhir untuk purpose, termasuk yang sudah kadaluarsa
+func (r *otpRepo) FindLatest(ctx context.Context, userID int, purpose string) (*models.UserOTP, error) {
+	var otp models.UserOTP
+
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND purpose = ?", userID, purpose).
+		Order("id DESC").
+		First(&otp).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,17 +54,43 @@ func (r *otpRepo) FindValidOTP(ctx context.Context, userID int, purpose string)
 	return &otp, nil
 }
 
-func (r *otpRepo) DeleteOTP(ctx context.Context, userID int, purpose string) error {
-	return r.db.Where("user_id = ? AND purpose = ?", userID, purpose).Delete(&models.UserOTP{}).Error
+// RegisterAttempt menambah counter percobaan secara atomik,
+// false jika batas percobaan sudah tercapai
+func (r *otpRepo) RegisterAttempt(ctx context.Context, id int, maxAttempts int) (bool, error) {
+	result := conn(ctx, r.db).
+		Model(&models.UserOTP{}).
+		Where("id = ? AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Consume menghapus OTP yang sudah dipakai, gagal jika sudah lebih dulu dipakai
+func (r *otpRepo) Consume(ctx context.Context, id int) error {
+	result := conn(ctx, r.db).Where("id = ?", id).Delete(&models.UserOTP{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
 }
 
-func (r *otpRepo) UpdateOTP(ctx context.Context, userID int, purpose string, hashedOTP string, otpExpiresTime time.Time) error {
-	return r.db.WithContext(ctx).Where("user_id = ? AND purpose = ?", userID, purpose).
-		Assign(map[string]interface{}{
-			"user_id":    userID,
-			"purpose":    purpose,
-			"otp":        hashedOTP,
-			"expires_at": otpExpiresTime,
-		}).
-		FirstOrCreate(&models.UserOTP{}).Error
+// Replace ganti OTP user untuk purpose yang sama dan antre email-nya dalam satu
+// transaksi, sehingga tidak ada OTP tanpa email atau email tanpa OTP
+func (r *otpRepo) Replace(ctx context.Context, otp *models.UserOTP, email *models.EmailOutbox) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ?", otp.UserID, otp.Purpose).Delete(&models.UserOTP{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(otp).Error; err != nil {
+			return err
+		}
+		return tx.Create(email).Error
+	})
 }
@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"context"
+	"mmgrapp/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PeriodRepository interface {
+	Create(ctx context.Context, period *models.Period) error
+	FindAll(ctx context.Context, userID int) ([]models.Period, error)
+	FindByID(ctx context.Context, id, userID int) (*models.Period, error)
+	FindDefault(ctx context.Context, userID int) (*models.Period, error)
+	CountByUser(ctx context.Context, userID int) (int64, error)
+	HasOverlap(ctx context.Context, userID int, startDate, endDate time.Time, excludeID int) (bool, error)
+	FindOverlap(ctx context.Context, userID int, startDate, endDate time.Time) (*models.Period, error)
+	Update(ctx context.Context, period *models.Period) error
+	SetDefault(ctx context.Context, id, userID, updatedBy int) error
+	CountTransactions(ctx context.Context, id int) (int64, error)
+	Delete(ctx context.Context, id, userID, deletedBy int) error
+}
+
+type periodRepo struct {
+	db *gorm.DB
+}
+
+func NewPeriodRepository(db *gorm.DB) PeriodRepository {
+	return &periodRepo{db: db}
+}
+
+// Create menyimpan periode, jika default maka default lama dilepas
+func (r *periodRepo) Create(ctx context.Context, period *models.Period) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if period.IsDefault {
+			if err := unsetDefaultPeriod(tx, period.UserID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(period).Error
+	})
+}
+
+func (r *periodRepo) FindAll(ctx context.Context, userID int) ([]models.Period, error) {
+	var periods []models.Period
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("start_date DESC").
+		Find(&periods).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+func (r *periodRepo) FindByID(ctx context.Context, id, userID int) (*models.Period, error) {
+	var period models.Period
+	err := conn(ctx, r.db).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&period).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &period, nil
+}
+
+func (r *periodRepo) FindDefault(ctx context.Context, userID int) (*models.Period, error) {
+	var period models.Period
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND is_default = ?", userID, true).
+		First(&period).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &period, nil
+}
+
+func (r *periodRepo) CountByUser(ctx context.Context, userID int) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&models.Period{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// HasOverlap cek apakah rentang tanggal bertabrakan dengan periode lain milik user
+func (r *periodRepo) HasOverlap(ctx context.Context, userID int, startDate, endDate time.Time, excludeID int) (bool, error) {
+	var count int64
+
+	query := overlapQuery(conn(ctx, r.db), userID, startDate, endDate)
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// FindOverlap periode paling awal yang bertabrakan dengan rentang tanggal
+func (r *periodRepo) FindOverlap(ctx context.Context, userID int, startDate, endDate time.Time) (*models.Period, error) {
+	var period models.Period
+	err := overlapQuery(conn(ctx, r.db), userID, startDate, endDate).
+		Order("start_date ASC").
+		First(&period).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &period, nil
+}
+
+func overlapQuery(db *gorm.DB, userID int, startDate, endDate time.Time) *gorm.DB {
+	return db.Model(&models.Period{}).
+		Where("user_id = ? AND start_date <= ? AND end_date >= ?", userID, endDate, startDate)
+}
+
+// Update simpan nama & rentang tanggal. is_default sengaja tidak ikut disimpan,
+// perpindahan default hanya lewat Create / SetDefault.
+func (r *periodRepo) Update(ctx context.Context, period *models.Period) error {
+	return conn(ctx, r.db).
+		Model(period).
+		Select("name", "start_date", "end_date", "updated_by", "updated_at").
+		Updates(period).Error
+}
+
+// SetDefault menjadikan periode sebagai satu-satunya default milik user
+func (r *periodRepo) SetDefault(ctx context.Context, id, userID, updatedBy int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := unsetDefaultPeriod(tx, userID); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Period{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Updates(map[string]interface{}{
+				"is_default": true,
+				"updated_by": updatedBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+}
+
+// CountTransactions menghitung pemasukan + pengeluaran di periode
+func (r *periodRepo) CountTransactions(ctx context.Context, id int) (int64, error) {
+	var incomes, expenses int64
+
+	if err := conn(ctx, r.db).Model(&models.Income{}).Where("period_id = ?", id).Count(&incomes).Error; err != nil {
+		return 0, err
+	}
+	if err := conn(ctx, r.db).Model(&models.Expense{}).Where("period_id = ?", id).Count(&expenses).Error; err != nil {
+		return 0, err
+	}
+
+	return incomes + expenses, nil
+}
+
+// Delete soft delete periode sekaligus mengisi deleted_by
+func (r *periodRepo) Delete(ctx context.Context, id, userID, deletedBy int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Period{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Update("deleted_by", deletedBy)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Period{}).Error
+	})
+}
+
+func unsetDefaultPeriod(tx *gorm.DB, userID int) error {
+	return tx.Model(&models.Period{}).
+		Where("user_id = ? AND is_default = ?", userID, true).
+		Update("is_default", false).Error
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"mmgrapp/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ProfileRepository interface {
+	Create(ctx context.Context, profile *models.Profile) error
+	FindByUserID(ctx context.Context, userID int) (*models.Profile, error)
+	Update(ctx context.Context, profile *models.Profile) error
+}
+
+type profileRepo struct {
+	db *gorm.DB
+}
+
+func NewProfileRepository(db *gorm.DB) ProfileRepository {
+	return &profileRepo{db: db}
+}
+
+func (r *profileRepo) Create(ctx context.Context, profile *models.Profile) error {
+	return conn(ctx, r.db).Create(profile).Error
+}
+
+func (r *profileRepo) FindByUserID(ctx context.Context, userID int) (*models.Profile, error) {
+	var profile models.Profile
+	err := conn(ctx, r.db).Where("user_id = ?", userID).First(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+func (r *profileRepo) Update(ctx context.Context, profile *models.Profile) error {
+	return conn(ctx, r.db).Save(profile).Error
+}
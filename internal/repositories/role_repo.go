@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"mmgrapp/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	FindByName(ctx context.Context, name string) (*models.Role, error)
+	FindRoleNames(ctx context.Context, userID int) ([]string, error)
+	FindPermissionNames(ctx context.Context, userID int) ([]string, error)
+	AssignRole(ctx context.Context, userID int, roleName string) error
+}
+
+type roleRepo struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepo{db: db}
+}
+
+func (r *roleRepo) FindByName(ctx context.Context, name string) (*models.Role, error) {
+	var role models.Role
+	if err := conn(ctx, r.db).Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindRoleNames nama role yang dimiliki user
+func (r *roleRepo) FindRoleNames(ctx context.Context, userID int) ([]string, error) {
+	names := []string{}
+	err := conn(ctx, r.db).
+		Model(&models.Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Pluck("roles.name", &names).Error
+	return names, err
+}
+
+// FindPermissionNames gabungan permission dari semua role user
+func (r *roleRepo) FindPermissionNames(ctx context.Context, userID int) ([]string, error) {
+	names := []string{}
+	err := conn(ctx, r.db).
+		Model(&models.Permission{}).
+		Distinct("permissions.name").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN user_roles ON user_roles.role_id = role_permissions.role_id").
+		Where("user_roles.user_id = ?", userID).
+		Pluck("permissions.name", &names).Error
+	return names, err
+}
+
+// AssignRole memberi role ke user, aman dipanggil berulang
+func (r *roleRepo) AssignRole(ctx context.Context, userID int, roleName string) error {
+	role, err := r.FindByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	user := models.User{ID: userID}
+	return conn(ctx, r.db).Model(&user).Association("Roles").Append(role)
+}
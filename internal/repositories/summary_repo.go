@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SummaryFilter batasan data untuk ringkasan, by periode atau rentang tanggal
+type SummaryFilter struct {
+	PeriodID  int
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// SummaryRepository agregasi (SUM / GROUP BY) atas tabel incomes & expenses
+type SummaryRepository interface {
+	TotalIncome(ctx context.Context, userID int, filter SummaryFilter) (float64, error)
+	TotalExpense(ctx context.Context, userID int, filter SummaryFilter) (float64, error)
+	AccountBalances(ctx context.Context, userID int, filter SummaryFilter) ([]dto.AccountSummary, error)
+	IncomeByCategory(ctx context.Context, userID int, filter SummaryFilter) ([]dto.CategorySummary, error)
+	ExpenseByCategory(ctx context.Context, userID int, filter SummaryFilter) ([]dto.CategorySummary, error)
+}
+
+type summaryRepo struct {
+	db *gorm.DB
+}
+
+func NewSummaryRepository(db *gorm.DB) SummaryRepository {
+	return &summaryRepo{db: db}
+}
+
+func (r *summaryRepo) TotalIncome(ctx context.Context, userID int, filter SummaryFilter) (float64, error) {
+	return r.totalAmount(ctx, &models.Income{}, userID, filter)
+}
+
+func (r *summaryRepo) TotalExpense(ctx context.Context, userID int, filter SummaryFilter) (float64, error) {
+	return r.totalAmount(ctx, &models.Expense{}, userID, filter)
+}
+
+// AccountBalances total pemasukan, pengeluaran & saldo per akun
+func (r *summaryRepo) AccountBalances(ctx context.Context, userID int, filter SummaryFilter) ([]dto.AccountSummary, error) {
+	db := conn(ctx, r.db)
+
+	incomes := r.scoped(db.Model(&models.Income{}), userID, filter).
+		Select("account_id, SUM(amount) AS total").
+		Group("account_id")
+	expenses := r.scoped(db.Model(&models.Expense{}), userID, filter).
+		Select("account_id, SUM(amount) AS total").
+		Group("account_id")
+
+	rows := []dto.AccountSummary{}
+	err := db.Model(&models.Account{}).
+		Select(`accounts.id AS account_id,
+			accounts.name AS account_name,
+			accounts.type AS account_type,
+			COALESCE(i.total, 0) AS total_income,
+			COALESCE(e.total, 0) AS total_expense,
+			COALESCE(i.total, 0) - COALESCE(e.total, 0) AS balance`).
+		Joins("LEFT JOIN (?) AS i ON i.account_id = accounts.id", incomes).
+		Joins("LEFT JOIN (?) AS e ON e.account_id = accounts.id", expenses).
+		Where("accounts.user_id = ?", userID).
+		Order("accounts.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *summaryRepo) IncomeByCategory(ctx context.Context, userID int, filter SummaryFilter) ([]dto.CategorySummary, error) {
+	return r.byCategory(ctx, &models.Income{}, userID, filter)
+}
+
+func (r *summaryRepo) ExpenseByCategory(ctx context.Context, userID int, filter SummaryFilter) ([]dto.CategorySummary, error) {
+	return r.byCategory(ctx, &models.Expense{}, userID, filter)
+}
+
+func (r *summaryRepo) totalAmount(ctx context.Context, model interface{}, userID int, filter SummaryFilter) (float64, error) {
+	var total float64
+	err := r.scoped(conn(ctx, r.db).Model(model), userID, filter).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *summaryRepo) byCategory(ctx context.Context, model interface{}, userID int, filter SummaryFilter) ([]dto.CategorySummary, error) {
+	rows := []dto.CategorySummary{}
+	err := r.scoped(conn(ctx, r.db).Model(model), userID, filter).
+		Select("category, SUM(amount) AS total, COUNT(*) AS count").
+		Group("category").
+		Order("total DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// scoped menerapkan user_id + filter periode / tanggal
+func (r *summaryRepo) scoped(query *gorm.DB, userID int, filter SummaryFilter) *gorm.DB {
+	query = query.Where("user_id = ?", userID)
+
+	if filter.PeriodID != 0 {
+		query = query.Where("period_id = ?", filter.PeriodID)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("date <= ?", *filter.EndDate)
+	}
+
+	return query
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// UnitOfWork menjalankan beberapa operasi repository dalam satu transaksi DB.
+// Transaksi dibawa lewat ctx, jadi semua repository yang dipanggil dengan ctx
+// dari fn otomatis ikut transaksi tanpa perlu mengubah signature-nya.
+type UnitOfWork interface {
+	// Do commit jika fn sukses, rollback jika fn mengembalikan error / panic.
+	// Do di dalam Do memakai transaksi luar yang sama.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+// afterCommitKey daftar fungsi yang menunggu transaksi aktif di-commit
+type afterCommitKey struct{}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	var hooks []func(ctx context.Context) error
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txCtx := context.WithValue(ctx, txKey{}, tx)
+		return fn(context.WithValue(txCtx, afterCommitKey{}, &hooks))
+	})
+	if err != nil {
+		return err
+	}
+
+	// data sudah tersimpan, kegagalan hook tidak bisa membatalkan apa-apa lagi
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			log.Printf("⚠️ Gagal menjalankan aksi setelah commit: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// AfterCommit jalankan fn setelah transaksi UnitOfWork di ctx berhasil di-commit
+// (dibuang jika rollback), untuk efek di luar DB seperti cache / denylist. Tanpa
+// transaksi aktif fn langsung dijalankan dan error-nya dikembalikan.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context) error) error {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*[]func(ctx context.Context) error)
+	if !ok {
+		return fn(ctx)
+	}
+
+	*hooks = append(*hooks, fn)
+	return nil
+}
+
+// conn koneksi untuk query repository: transaksi aktif dari UnitOfWork jika
+// ada, selain itu db biasa
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
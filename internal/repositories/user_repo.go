@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"mmgrapp/internal/models"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -13,6 +14,22 @@ type UserRepository interface {
 	FindByUsername(ctx context.Context, username string) (*models.User, error)
 	VerifyUser(ctx context.Context, email string) (*models.User, error)
 	FindByID(ctx context.Context, id int) (*models.User, error)
+	FindByIDWithProfile(ctx context.Context, id int) (*models.User, error)
+	FindDetailByID(ctx context.Context, id int) (*models.User, error)
+	FindAll(ctx context.Context, filter UserFilter) ([]models.User, int64, error)
+	UpdateFields(ctx context.Context, id int, fields map[string]interface{}) error
+	IsEmailTaken(ctx context.Context, email string) (bool, error)
+	FindLocale(ctx context.Context, userID int) (string, error)
+	Delete(ctx context.Context, id, deletedBy int) error
+}
+
+// UserFilter filter & pagination untuk list user (admin)
+type UserFilter struct {
+	Search     string // cari di username / email
+	IsActive   *bool
+	IsVerified *bool
+	Page       int
+	Limit      int
 }
 
 type userRepository struct {
@@ -24,12 +41,12 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
-	return r.db.Create(user).Error
+	return conn(ctx, r.db).Create(user).Error
 }
 
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := conn(ctx, r.db).Where("email = ?", email).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -37,7 +54,7 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models
 
 func (r *userRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := conn(ctx, r.db).Where("username = ?", username).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -51,7 +68,7 @@ func (r *userRepository) VerifyUser(ctx context.Context, email string) (*models.
 	}
 
 	// 2. Update flag is_verified
-	if err := r.db.WithContext(ctx).
+	if err := conn(ctx, r.db).
 		Model(user).
 		Update("is_verified", true).Error; err != nil {
 		return nil, err
@@ -62,6 +79,131 @@ func (r *userRepository) VerifyUser(ctx context.Context, email string) (*models.
 
 func (r *userRepository) FindByID(ctx context.Context, id int) (*models.User, error) {
 	var user models.User
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
+	err := conn(ctx, r.db).Where("id = ?", id).First(&user).Error
+	return &user, err
+}
+
+func (r *userRepository) FindByIDWithProfile(ctx context.Context, id int) (*models.User, error) {
+	var user models.User
+	err := conn(ctx, r.db).Preload("Profile").Where("id = ?", id).First(&user).Error
 	return &user, err
 }
+
+// FindDetailByID user beserta profile & role
+func (r *userRepository) FindDetailByID(ctx context.Context, id int) (*models.User, error) {
+	var user models.User
+	err := conn(ctx, r.db).
+		Preload("Profile").
+		Preload("Roles").
+		Where("id = ?", id).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// likeWildcards escape karakter khusus LIKE supaya input user dicari apa adanya,
+// dipakai bersama klausa ESCAPE '\'
+var likeWildcards = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// likeContains pola LIKE "mengandung s"
+func likeContains(s string) string {
+	return "%" + likeWildcards.Replace(s) + "%"
+}
+
+func (r *userRepository) FindAll(ctx context.Context, filter UserFilter) ([]models.User, int64, error) {
+	var (
+		users []models.User
+		total int64
+	)
+
+	query := conn(ctx, r.db).Model(&models.User{})
+
+	if filter.Search != "" {
+		keyword := likeContains(strings.ToLower(filter.Search))
+		query = query.Where(`LOWER(username) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\'`, keyword, keyword)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+	if filter.IsVerified != nil {
+		query = query.Where("is_verified = ?", *filter.IsVerified)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Roles").
+		Order("id DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// UpdateFields update sebagian kolom user, ErrRecordNotFound jika user tidak ada
+func (r *userRepository) UpdateFields(ctx context.Context, id int, fields map[string]interface{}) error {
+	result := conn(ctx, r.db).
+		Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// IsEmailTaken cek email termasuk milik user yang sudah di-soft delete,
+// karena unique index tetap berlaku untuk baris tersebut
+func (r *userRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Unscoped().
+		Model(&models.User{}).
+		Where("email = ?", email).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindLocale bahasa pilihan user dari profile, kosong jika profile belum ada
+func (r *userRepository) FindLocale(ctx context.Context, userID int) (string, error) {
+	var locales []string
+	err := conn(ctx, r.db).
+		Model(&models.Profile{}).
+		Where("user_id = ?", userID).
+		Limit(1).
+		Pluck("locale", &locales).Error
+	if err != nil || len(locales) == 0 {
+		return "", err
+	}
+	return locales[0], nil
+}
+
+// Delete soft delete user sekaligus mengisi deleted_by
+func (r *userRepository) Delete(ctx context.Context, id, deletedBy int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("id = ?", id).
+			Update("deleted_by", deletedBy)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Where("id = ?", id).Delete(&models.User{}).Error
+	})
+}
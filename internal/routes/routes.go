@@ -1,11 +1,14 @@
 package routes
 
 import (
+	"log"
 	config "mmgrapp/internal/configs"
 	"mmgrapp/internal/handlers"
 	"mmgrapp/internal/middlewares"
+	"mmgrapp/internal/models"
 	"mmgrapp/internal/repositories"
 	"mmgrapp/internal/services"
+	"mmgrapp/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,39 +16,195 @@ import (
 // SetupRoutes mendaftarkan semua route ke server
 func SetupRoutes(r *gin.Engine) {
 	db := config.DB
+
+	// operasi multi-langkah di service dibungkus transaksi lewat unit of work
+	uow := repositories.NewUnitOfWork(db)
+
+	// ================= PERIOD MODULE =================
+	periodRepo := repositories.NewPeriodRepository(db)
+	periodService := services.NewPeriodService(periodRepo, uow)
+	periodHandler := handlers.NewPeriodHandler(periodService)
+
+	// ================= EMAIL OUTBOX =================
+	// email tidak dikirim langsung, tapi diantre lalu dikirim worker (cmd/server)
+	emailOutboxRepo := repositories.NewEmailOutboxRepository(db)
+
 	// ================= USER MODULE =================
 	userRepo := repositories.NewUserRepository(db)
-	otpRepo := repositories.NewOTPRepository(db)
-	userService := services.NewUserService(userRepo, otpRepo)
+	loginAttemptRepo := repositories.NewLoginAttemptRepository(db)
+	otpService := services.NewOTPService(repositories.NewOTPRepository(db), userRepo)
+	userService := services.NewUserService(userRepo, otpService, periodService, loginAttemptRepo, emailOutboxRepo, uow)
 	userHandler := handlers.NewUserHandler(userService)
 
+	// ================= RBAC =================
+	roleRepo := repositories.NewRoleRepository(db)
+
+	// token, status akun & permission dicek ke DB di setiap request terproteksi
+	authMiddleware := middlewares.JWTAuthMiddleware(userRepo, roleRepo)
+
+	// ================= RATE LIMIT =================
+	// endpoint pengirim OTP dibatasi per IP, per email & per email + purpose.
+	// Batas bisa diatur lewat env dengan format "<jumlah>/<durasi>".
+	otpPerIP := rateFromEnv("RATE_LIMIT_OTP_IP", "20/1h")
+	otpPerEmail := rateFromEnv("RATE_LIMIT_OTP_EMAIL", "10/1h")
+	otpPerPurpose := rateFromEnv("RATE_LIMIT_OTP_PURPOSE", "3/15m")
+	otpRateLimit := func(emailField, purpose string) gin.HandlerFunc {
+		return middlewares.RateLimit(
+			middlewares.RateLimitRule{Name: "otp-ip", Rate: otpPerIP, Key: middlewares.KeyByIP},
+			middlewares.RateLimitRule{Name: "otp-email", Rate: otpPerEmail, Key: middlewares.KeyByJSONField(emailField)},
+			middlewares.RateLimitRule{Name: "otp-purpose", Rate: otpPerPurpose, Key: middlewares.KeyByEmailPurpose(emailField, purpose)},
+		)
+	}
+
 	// ================= AUTH MODULE =================
 	authRepo := repositories.NewAuthRepository(db)
-	authService := services.NewAuthService(authRepo, userRepo, otpRepo)
+	mfaRepo := repositories.NewMFARepository(db)
+	authService := services.NewAuthService(authRepo, userRepo, otpService, roleRepo, mfaRepo, loginAttemptRepo, emailOutboxRepo, uow)
 	authHandler := handlers.NewAuthHandler(authService)
 
+	// ================= MFA MODULE =================
+	mfaService := services.NewMFAService(mfaRepo, userRepo, loginAttemptRepo, uow, config.GetEnv("MFA_ISSUER", "MoneyApp"))
+	mfaHandler := handlers.NewMFAHandler(mfaService)
+
+	// ================= ADMIN MODULE =================
+	adminUserService := services.NewAdminUserService(userRepo, authService, otpService, uow)
+	adminUserHandler := handlers.NewAdminUserHandler(adminUserService)
+	emailOutboxService := services.NewEmailOutboxService(emailOutboxRepo)
+	adminEmailHandler := handlers.NewAdminEmailHandler(emailOutboxService)
+
+	// ================= PROFILE MODULE =================
+	profileRepo := repositories.NewProfileRepository(db)
+	profileService := services.NewProfileService(profileRepo)
+	profileHandler := handlers.NewProfileHandler(profileService)
+
+	// ================= ACCOUNT MODULE =================
+	accountRepo := repositories.NewAccountRepository(db)
+	accountService := services.NewAccountService(accountRepo)
+	accountHandler := handlers.NewAccountHandler(accountService)
+
+	// ================= INCOME MODULE =================
+	incomeRepo := repositories.NewIncomeRepository(db)
+	incomeService := services.NewIncomeService(incomeRepo, accountRepo, periodRepo)
+	incomeHandler := handlers.NewIncomeHandler(incomeService)
+
+	// ================= EXPENSE MODULE =================
+	expenseRepo := repositories.NewExpenseRepository(db)
+	expenseService := services.NewExpenseService(expenseRepo, accountRepo, periodRepo)
+	expenseHandler := handlers.NewExpenseHandler(expenseService)
+
+	// ================= SUMMARY MODULE =================
+	summaryRepo := repositories.NewSummaryRepository(db)
+	summaryService := services.NewSummaryService(summaryRepo, periodRepo)
+	summaryHandler := handlers.NewSummaryHandler(summaryService)
+
 	// Test endpoint
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "pong"})
 	})
 
+	// public key JWT untuk service lain
+	r.GET("/.well-known/jwks.json", handlers.JWKS)
+
 	api := r.Group("/api")
 	{
 		auth := api.Group("/auth")
 		// user module
-		auth.POST("/register", userHandler.Register)
+		auth.POST("/register", otpRateLimit("email", services.OTPPurposeEmailVerification), userHandler.Register)
 		auth.POST("/verify-email", userHandler.VerifyEmail)
-		auth.POST("/resend-otp", userHandler.ResendOTP)
+		auth.POST("/resend-otp", otpRateLimit("email", ""), userHandler.ResendOTP)
 
 		// auth module
 		auth.POST("/login", authHandler.Login)
-		auth.POST("/forgot-pass", authHandler.ForgotPassword)
+		auth.POST("/login/mfa", authHandler.VerifyMFA)
+		auth.POST("/forgot-pass", otpRateLimit("email", services.OTPPurposePasswordReset), authHandler.ForgotPassword)
 		auth.POST("/reset-pass", authHandler.ResetPassword)
+		auth.POST("/unlock-account/request", otpRateLimit("email", services.OTPPurposeAccountUnlock), authHandler.RequestUnlock)
+		auth.POST("/unlock-account", authHandler.UnlockAccount)
 		auth.POST("/refresh-token", authHandler.RefreshToken)
 		auth.POST("/logout", authHandler.RevokeToken)
+		auth.POST("/logout-all", authMiddleware, authHandler.LogoutAll)
+		auth.PUT("/password", authMiddleware, authHandler.ChangePassword)
+		auth.POST("/email/change", authMiddleware, otpRateLimit("new_email", services.OTPPurposeEmailChange), userHandler.RequestEmailChange)
+		auth.POST("/email/verify", authMiddleware, userHandler.ConfirmEmailChange)
+		auth.GET("/sessions", authMiddleware, authHandler.ListSessions)
+		auth.DELETE("/sessions/:id", authMiddleware, authHandler.RevokeSession)
+
+		mfa := auth.Group("/mfa", authMiddleware)
+		// mfa module
+		mfa.GET("", mfaHandler.Status)
+		mfa.POST("/setup", mfaHandler.Setup)
+		mfa.POST("/enable", mfaHandler.Enable)
+		mfa.POST("/disable", mfaHandler.Disable)
+		mfa.POST("/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
 
 		profile := api.Group("/profile")
 		// profile module
-		profile.GET("/my-detail/:id", middlewares.JWTAuthMiddleware(), userHandler.MyDetail)
+		profile.GET("/my-detail/:id", authMiddleware, userHandler.MyDetail)
+		profile.GET("/me", authMiddleware, profileHandler.GetMe)
+		profile.PUT("/me", authMiddleware, profileHandler.UpdateMe)
+
+		accounts := api.Group("/accounts", authMiddleware)
+		// account module
+		accounts.POST("", accountHandler.Create)
+		accounts.GET("", accountHandler.List)
+		accounts.GET("/:id", accountHandler.GetByID)
+		accounts.PUT("/:id", accountHandler.Update)
+		accounts.PATCH("/:id/archive", accountHandler.Archive)
+		accounts.PATCH("/:id/activate", accountHandler.Activate)
+		accounts.DELETE("/:id", accountHandler.Delete)
+
+		periods := api.Group("/periods", authMiddleware)
+		// period module
+		periods.POST("", periodHandler.Create)
+		periods.GET("", periodHandler.List)
+		periods.GET("/default", periodHandler.GetDefault)
+		periods.GET("/:id", periodHandler.GetByID)
+		periods.PUT("/:id", periodHandler.Update)
+		periods.PATCH("/:id/default", periodHandler.SetDefault)
+		periods.DELETE("/:id", periodHandler.Delete)
+
+		incomes := api.Group("/incomes", authMiddleware)
+		// income module
+		incomes.POST("", incomeHandler.Create)
+		incomes.GET("", incomeHandler.List)
+		incomes.GET("/:id", incomeHandler.GetByID)
+		incomes.PUT("/:id", incomeHandler.Update)
+		incomes.DELETE("/:id", incomeHandler.Delete)
+
+		expenses := api.Group("/expenses", authMiddleware)
+		// expense module
+		expenses.POST("", expenseHandler.Create)
+		expenses.GET("", expenseHandler.List)
+		expenses.GET("/:id", expenseHandler.GetByID)
+		expenses.PUT("/:id", expenseHandler.Update)
+		expenses.DELETE("/:id", expenseHandler.Delete)
+
+		// summary module
+		api.GET("/summary", authMiddleware, summaryHandler.GetSummary)
+
+		adminUsers := api.Group("/admin/users", authMiddleware)
+		// admin module
+		adminUsers.GET("", middlewares.RequirePermission(models.PermUsersRead), adminUserHandler.List)
+		adminUsers.GET("/:id", middlewares.RequirePermission(models.PermUsersRead), adminUserHandler.GetByID)
+		adminUsers.PATCH("/:id/deactivate", middlewares.RequirePermission(models.PermUsersManage), adminUserHandler.Deactivate)
+		adminUsers.PATCH("/:id/activate", middlewares.RequirePermission(models.PermUsersManage), adminUserHandler.Activate)
+		adminUsers.PATCH("/:id/verify", middlewares.RequirePermission(models.PermUsersManage), adminUserHandler.Verify)
+		adminUsers.POST("/:id/reset-password", middlewares.RequirePermission(models.PermUsersManage), adminUserHandler.ForcePasswordReset)
+		adminUsers.DELETE("/:id", middlewares.RequirePermission(models.PermUsersDelete), adminUserHandler.Delete)
+
+		adminEmails := api.Group("/admin/emails", authMiddleware)
+		// admin module - outbox email
+		adminEmails.GET("", middlewares.RequirePermission(models.PermEmailsRead), adminEmailHandler.List)
+		adminEmails.GET("/:id", middlewares.RequirePermission(models.PermEmailsRead), adminEmailHandler.GetByID)
+		adminEmails.POST("/:id/retry", middlewares.RequirePermission(models.PermEmailsManage), adminEmailHandler.Retry)
+	}
+}
+
+// rateFromEnv baca batas rate limit dari env, server berhenti jika format salah
+func rateFromEnv(key, fallback string) utils.Rate {
+	rate, err := utils.ParseRate(config.GetEnv(key, fallback))
+	if err != nil {
+		log.Fatalf("❌ Konfigurasi %s tidak valid: %v", key, err)
 	}
+	return rate
 }
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var ErrAccountHasTransactions = errors.New("akun masih memiliki transaksi, arsipkan akun jika tidak ingin dipakai lagi")
+
+type AccountService interface {
+	Create(ctx context.Context, userID int, req dto.AccountRequest) (*models.Account, error)
+	List(ctx context.Context, userID int, includeInactive bool) ([]models.Account, error)
+	GetByID(ctx context.Context, userID, id int) (*models.Account, error)
+	Update(ctx context.Context, userID, id int, req dto.AccountRequest) (*models.Account, error)
+	SetActive(ctx context.Context, userID, id int, isActive bool) (*models.Account, error)
+	Delete(ctx context.Context, userID, id int) error
+}
+
+type accountService struct {
+	repo repositories.AccountRepository
+}
+
+func NewAccountService(accountRepo repositories.AccountRepository) AccountService {
+	return &accountService{repo: accountRepo}
+}
+
+func (s *accountService) Create(ctx context.Context, userID int, req dto.AccountRequest) (*models.Account, error) {
+	account := &models.Account{
+		UserID:      userID,
+		Name:        req.Name,
+		Type:        req.Type,
+		Description: req.Description,
+		IsActive:    true,
+		CreatedBy:   &userID,
+		UpdatedBy:   &userID,
+	}
+
+	if err := s.repo.Create(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (s *accountService) List(ctx context.Context, userID int, includeInactive bool) ([]models.Account, error) {
+	return s.repo.FindAll(ctx, userID, includeInactive)
+}
+
+func (s *accountService) GetByID(ctx context.Context, userID, id int) (*models.Account, error) {
+	account, err := s.repo.FindByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (s *accountService) Update(ctx context.Context, userID, id int, req dto.AccountRequest) (*models.Account, error) {
+	account, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	account.Name = req.Name
+	account.Type = req.Type
+	account.Description = req.Description
+	account.UpdatedBy = &userID
+
+	if err := s.repo.Update(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// SetActive archive (isActive=false) atau aktifkan kembali akun
+func (s *accountService) SetActive(ctx context.Context, userID, id int, isActive bool) (*models.Account, error) {
+	if err := s.repo.SetActive(ctx, id, userID, isActive, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	return s.GetByID(ctx, userID, id)
+}
+
+func (s *accountService) Delete(ctx context.Context, userID, id int) error {
+	if _, err := s.GetByID(ctx, userID, id); err != nil {
+		return err
+	}
+
+	// akun yang masih dipakai transaksi tidak boleh dihapus
+	count, err := s.repo.CountTransactions(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAccountHasTransactions
+	}
+
+	err = s.repo.Delete(ctx, id, userID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAccountNotFound
+	}
+
+	return err
+}
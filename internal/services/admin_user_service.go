@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var ErrCannotModifySelf = errors.New("tidak dapat mengubah status akun sendiri")
+
+type AdminUserService interface {
+	List(ctx context.Context, filter repositories.UserFilter) ([]models.User, *dto.PaginationMeta, error)
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	SetActive(ctx context.Context, adminID, id int, isActive bool) (*models.User, error)
+	Verify(ctx context.Context, adminID, id int) (*models.User, error)
+	ForcePasswordReset(ctx context.Context, adminID, id int) error
+	Delete(ctx context.Context, adminID, id int) error
+}
+
+type adminUserService struct {
+	userRepo    repositories.UserRepository
+	authService AuthService
+	otp         OTPService
+	uow         repositories.UnitOfWork
+}
+
+func NewAdminUserService(userRepo repositories.UserRepository, authService AuthService, otpService OTPService, uow repositories.UnitOfWork) AdminUserService {
+	return &adminUserService{
+		userRepo:    userRepo,
+		authService: authService,
+		otp:         otpService,
+		uow:         uow,
+	}
+}
+
+func (s *adminUserService) List(ctx context.Context, filter repositories.UserFilter) ([]models.User, *dto.PaginationMeta, error) {
+	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
+
+	users, total, err := s.userRepo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return users, dto.NewPaginationMeta(filter.Page, filter.Limit, total), nil
+}
+
+func (s *adminUserService) GetByID(ctx context.Context, id int) (*models.User, error) {
+	user, err := s.userRepo.FindDetailByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// SetActive nonaktifkan / aktifkan kembali user. Saat dinonaktifkan semua sesi
+// & access token user langsung dicabut.
+func (s *adminUserService) SetActive(ctx context.Context, adminID, id int, isActive bool) (*models.User, error) {
+	if adminID == id {
+		return nil, ErrCannotModifySelf
+	}
+
+	// status & pencabutan sesi dalam satu transaksi, user tidak pernah
+	// nonaktif dengan refresh token yang masih berlaku
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.updateFields(ctx, id, map[string]interface{}{
+			"is_active":  isActive,
+			"updated_by": adminID,
+		}); err != nil {
+			return err
+		}
+
+		if isActive {
+			return nil
+		}
+		return s.authService.LogoutAll(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *adminUserService) Verify(ctx context.Context, adminID, id int) (*models.User, error) {
+	if err := s.updateFields(ctx, id, map[string]interface{}{
+		"is_verified": true,
+		"updated_by":  adminID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// ForcePasswordReset mengacak password user, mencabut semua sesi, lalu
+// mengirim OTP reset password ke email user. Semua langkah dalam satu
+// transaksi; cooldown OTP tidak berlaku karena reset dipicu admin.
+func (s *adminUserService) ForcePasswordReset(ctx context.Context, adminID, id int) error {
+	user, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	randomPassword, err := utils.GenerateRandomID(32)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(randomPassword)
+	if err != nil {
+		return errors.New("gagal meng-hash password")
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.updateFields(ctx, id, map[string]interface{}{
+			"password":   hashedPassword,
+			"updated_by": adminID,
+		}); err != nil {
+			return err
+		}
+
+		if err := s.authService.LogoutAll(ctx, id); err != nil {
+			return err
+		}
+
+		return s.otp.Issue(ctx, user, OTPPurposePasswordReset, "")
+	})
+}
+
+func (s *adminUserService) Delete(ctx context.Context, adminID, id int) error {
+	if adminID == id {
+		return ErrCannotModifySelf
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Delete(ctx, id, adminID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		return s.authService.LogoutAll(ctx, id)
+	})
+}
+
+func (s *adminUserService) updateFields(ctx context.Context, id int, fields map[string]interface{}) error {
+	err := s.userRepo.UpdateFields(ctx, id, fields)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+
+	return err
+}
@@ -4,36 +4,70 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"mmgrapp/internal/dto"
 	"mmgrapp/internal/models"
 	"mmgrapp/internal/repositories"
 	"mmgrapp/pkg/utils"
+	"strconv"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRefreshTokenReused  = errors.New("refresh token sudah tidak berlaku, silakan login ulang")
+	ErrSessionNotFound     = errors.New("sesi tidak ditemukan")
+	ErrAccountNotLocked    = errors.New("akun tidak sedang terkunci")
+	ErrInvalidMFAChallenge = errors.New("token MFA tidak valid atau kadaluarsa, silakan login ulang")
+	ErrWrongPassword       = errors.New("password saat ini salah")
 )
 
 type AuthService interface {
-	Login(ctx context.Context, username, password string) (interface{}, error)
+	Login(ctx context.Context, username, password string, client dto.ClientInfo) (interface{}, error)
+	VerifyMFA(ctx context.Context, mfaToken, code string, client dto.ClientInfo) (interface{}, error)
 	ForgotPassword(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, email, otp, newPassword string) error
-	RefreshToken(ctx context.Context, oldRefreshToken string) (map[string]interface{}, error)
+	RefreshToken(ctx context.Context, oldRefreshToken string, client dto.ClientInfo) (map[string]interface{}, error)
 	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context, userID int) error
+	ChangePassword(ctx context.Context, userID int, currentJTI, currentPassword, newPassword string) error
+	ListSessions(ctx context.Context, userID int) ([]dto.SessionResponse, error)
+	RevokeSession(ctx context.Context, userID, sessionID int) error
+	RequestUnlock(ctx context.Context, email string) error
+	UnlockAccount(ctx context.Context, email, otp string) error
 }
 
 type authService struct {
 	authRepo repositories.AuthRepository
 	userRepo repositories.UserRepository
-	otpRepo  repositories.OTPRepository
+	otp      OTPService
+	roleRepo repositories.RoleRepository
+	mfaRepo  repositories.MFARepository
+	guard    *loginGuard
+	outbox   repositories.EmailOutboxRepository
+	uow      repositories.UnitOfWork
 }
 
-func NewAuthService(authRepo repositories.AuthRepository, userRepo repositories.UserRepository, otpRepo repositories.OTPRepository) AuthService {
+func NewAuthService(authRepo repositories.AuthRepository, userRepo repositories.UserRepository, otpService OTPService, roleRepo repositories.RoleRepository, mfaRepo repositories.MFARepository, loginAttemptRepo repositories.LoginAttemptRepository, outboxRepo repositories.EmailOutboxRepository, uow repositories.UnitOfWork) AuthService {
 	return &authService{
 		authRepo: authRepo,
 		userRepo: userRepo,
-		otpRepo:  otpRepo,
+		otp:      otpService,
+		roleRepo: roleRepo,
+		mfaRepo:  mfaRepo,
+		guard:    &loginGuard{repo: loginAttemptRepo},
+		outbox:   outboxRepo,
+		uow:      uow,
 	}
 }
 
-func (s *authService) Login(ctx context.Context, username, password string) (interface{}, error) {
+func (s *authService) Login(ctx context.Context, username, password string, client dto.ClientInfo) (interface{}, error) {
+	// 0. IP yang terlalu banyak gagal ditolak sebelum cek apapun
+	if err := s.guard.check(ctx, ipLockPolicy, client.IPAddress); err != nil {
+		return nil, err
+	}
+
 	// 1. Coba login pakai email
 	user, err := s.userRepo.FindByEmail(ctx, username)
 
@@ -41,22 +75,150 @@ func (s *authService) Login(ctx context.Context, username, password string) (int
 	if err != nil {
 		user, err = s.userRepo.FindByUsername(ctx, username)
 		if err != nil {
+			if lockErr := s.guard.fail(ctx, ipLockPolicy, client.IPAddress); lockErr != nil {
+				return nil, lockErr
+			}
 			return nil, fmt.Errorf("email/username atau password salah")
 		}
 	}
 
-	// 3. Cek Password
+	// 3. Akun terkunci / masih jeda → tolak walau password benar
+	userKey := strconv.Itoa(user.ID)
+	if err := s.guard.check(ctx, userLockPolicy, userKey); err != nil {
+		return nil, err
+	}
+
+	// 4. Cek Password
 	if !utils.CheckPasswordHash(password, user.Password) {
+		userErr := s.guard.fail(ctx, userLockPolicy, userKey)
+		ipErr := s.guard.fail(ctx, ipLockPolicy, client.IPAddress)
+		if userErr != nil {
+			s.notifyAccountLocked(ctx, user, userErr, client)
+			return nil, userErr
+		}
+		if ipErr != nil {
+			return nil, ipErr
+		}
 		return nil, errors.New("email/username atau password salah")
 	}
 
-	// 4. Cek Verified status
-	if !user.IsVerified {
-		return nil, errors.New("email belum diverifikasi")
+	// 5. Cek status akun (aktif & verified)
+	if err := CheckUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	// 6. MFA aktif → token baru terbit setelah kode diverifikasi lewat VerifyMFA.
+	// Hitungan gagal belum direset supaya tebakan kode tetap kena lockout.
+	if _, err := findEnabledMFA(ctx, s.mfaRepo, user.ID); err == nil {
+		mfaToken, err := utils.GenerateMFAChallengeToken(user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{
+			"mfa_required": true,
+			"token_type":   "mfa",
+			"mfa_token":    mfaToken,
+			"expires_in":   int(utils.MFAChallengeTTL.Seconds()),
+		}, nil
+	} else if !errors.Is(err, ErrMFANotEnabled) {
+		return nil, err
+	}
+
+	if err := s.guard.reset(ctx, userLockPolicy, userKey); err != nil {
+		return nil, err
+	}
+
+	return s.issueSession(ctx, user, client)
+}
+
+// VerifyMFA langkah kedua login: tukar token tantangan + kode TOTP / recovery
+// code dengan access & refresh token
+func (s *authService) VerifyMFA(ctx context.Context, mfaToken, code string, client dto.ClientInfo) (interface{}, error) {
+	claims, err := utils.VerifyJWT(mfaToken)
+	if err != nil || claims.Type != "mfa" || claims.ID == "" {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	// token tantangan hanya sekali pakai
+	used, err := utils.CurrentTokenDenylist().Contains(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	userKey := strconv.Itoa(claims.UserID)
+	if err := s.guard.check(ctx, ipLockPolicy, client.IPAddress); err != nil {
+		return nil, err
+	}
+	if err := s.guard.check(ctx, userLockPolicy, userKey); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		user = nil
+	}
+	if err := CheckUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	mfa, err := findEnabledMFA(ctx, s.mfaRepo, user.ID)
+	if err != nil {
+		if errors.Is(err, ErrMFANotEnabled) {
+			return nil, ErrInvalidMFAChallenge
+		}
+		return nil, err
+	}
+
+	if err := verifyMFACode(ctx, s.mfaRepo, mfa, code); err != nil {
+		if !errors.Is(err, ErrInvalidMFACode) {
+			return nil, err
+		}
+
+		userErr := s.guard.fail(ctx, userLockPolicy, userKey)
+		ipErr := s.guard.fail(ctx, ipLockPolicy, client.IPAddress)
+		if userErr != nil {
+			s.notifyAccountLocked(ctx, user, userErr, client)
+			return nil, userErr
+		}
+		if ipErr != nil {
+			return nil, ipErr
+		}
+		return nil, err
+	}
+
+	// klaim token tantangan secara atomik, request paralel dengan token yang
+	// sama hanya satu yang mendapat sesi
+	claimed, err := utils.CurrentTokenDenylist().TryAdd(ctx, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	if err := s.guard.reset(ctx, userLockPolicy, userKey); err != nil {
+		return nil, err
 	}
 
+	return s.issueSession(ctx, user, client)
+}
+
+// issueSession terbitkan access token + refresh token family baru untuk login
+func (s *authService) issueSession(ctx context.Context, user *models.User, client dto.ClientInfo) (interface{}, error) {
 	// generate JWT
-	accessToken, err := utils.GenerateAccessToken(user.ID, user.IsAdmin)
+	roles, err := s.roleRepo.FindRoleNames(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, accessJTI, err := utils.GenerateAccessToken(user.ID, roles)
 	if err != nil {
 		return nil, err
 	}
@@ -67,11 +229,22 @@ func (s *authService) Login(ctx context.Context, username, password string) (int
 		return nil, err
 	}
 
+	// login baru = family token baru
+	familyID, err := utils.GenerateRandomID(16)
+	if err != nil {
+		return nil, err
+	}
+
 	// save refresh token
 	rt := &models.RefreshToken{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
+		UserID:      user.ID,
+		Token:       refreshToken,
+		FamilyID:    familyID,
+		AccessJTI:   accessJTI,
+		ExpiresAt:   time.Now().Add(7 * 24 * time.Hour),
+		UserAgent:   client.UserAgent,
+		IPAddress:   client.IPAddress,
+		DeviceLabel: deviceLabel(client),
 	}
 	if err := s.authRepo.CreateRefreshToken(ctx, rt); err != nil {
 		return nil, err
@@ -100,87 +273,176 @@ func (s *authService) ForgotPassword(ctx context.Context, email string) error {
 		return errors.New("email tidak ditemukan")
 	}
 
-	otp, hashedOTP, err := utils.GenerateOTP()
+	return s.otp.Send(ctx, user, OTPPurposePasswordReset, "")
+}
+
+func (s *authService) ResetPassword(ctx context.Context, email, otp, newPassword string) error {
+	// cek user email
+	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return errors.New("gagal generate OTP")
+		return errors.New("email tidak ditemukan")
 	}
 
-	if err := utils.SendOTP(user.Email, otp); err != nil {
+	// cek valid OTP
+	storedOTP, err := s.otp.Verify(ctx, user.ID, OTPPurposePasswordReset, otp)
+	if err != nil {
 		return err
 	}
 
-	return s.otpRepo.Create(ctx, &models.UserOTP{
-		UserID:    user.ID,
-		OTP:       hashedOTP,
-		Purpose:   "password_reset",
-		ExpiresAt: time.Now().Add(5 * time.Minute),
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return errors.New("gagal meng-hash password")
+	}
+
+	user.Password = hashedPassword
+	user.UpdatedAt = time.Now()
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		// OTP hanya bisa dipakai sekali
+		if err := s.otp.Consume(ctx, storedOTP); err != nil {
+			return err
+		}
+
+		if err := s.authRepo.UpdatePassword(ctx, user); err != nil {
+			return err
+		}
+
+		// reset lewat email membuktikan kepemilikan akun → kunci login dibuka
+		if err := s.guard.reset(ctx, userLockPolicy, strconv.Itoa(user.ID)); err != nil {
+			return err
+		}
+
+		// password baru → semua sesi lama harus login ulang
+		if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+			return err
+		}
+
+		queueSecurityAlert(ctx, s.outbox, userLocale(ctx, s.userRepo, user.ID), user.Email, user, securityEventPasswordChanged, "", "")
+		return nil
 	})
 }
 
-func (s *authService) ResetPassword(ctx context.Context, email, otp, newPassword string) error {
-	// cek user email
+// RequestUnlock kirim OTP untuk membuka akun yang terkunci karena gagal login
+func (s *authService) RequestUnlock(ctx context.Context, email string) error {
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
 		return errors.New("email tidak ditemukan")
 	}
 
-	// cek valid OTP
-	_, err = s.otpRepo.FindValidOTP(ctx, user.ID, "password_reset")
-	if err != nil {
-		return errors.New("OTP tidak valid atau kadaluarsa")
+	// hanya akun yang benar-benar terkunci (bukan sekadar jeda) yang perlu dibuka
+	var lockErr *LoginLockError
+	err = s.guard.check(ctx, userLockPolicy, strconv.Itoa(user.ID))
+	if err != nil && !errors.As(err, &lockErr) {
+		return err
+	}
+	if lockErr == nil || lockErr.LockedUntil == nil {
+		return ErrAccountNotLocked
 	}
 
-	hashedPassword, _ := utils.HashPassword(newPassword)
+	return s.otp.Send(ctx, user, OTPPurposeAccountUnlock, "")
+}
 
-	user.Password = hashedPassword
-	user.UpdatedAt = time.Now()
+// UnlockAccount buka kunci login akun memakai OTP dari RequestUnlock
+func (s *authService) UnlockAccount(ctx context.Context, email, otp string) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return errors.New("email tidak ditemukan")
+	}
 
-	s.otpRepo.DeleteOTP(ctx, user.ID, "password_reset")
+	storedOTP, err := s.otp.Verify(ctx, user.ID, OTPPurposeAccountUnlock, otp)
+	if err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.otp.Consume(ctx, storedOTP); err != nil {
+			return err
+		}
 
-	return s.authRepo.UpdatePassword(ctx, user)
+		return s.guard.reset(ctx, userLockPolicy, strconv.Itoa(user.ID))
+	})
 }
 
-func (s *authService) RefreshToken(ctx context.Context, oldRefreshToken string) (map[string]interface{}, error) {
+func (s *authService) RefreshToken(ctx context.Context, oldRefreshToken string, client dto.ClientInfo) (map[string]interface{}, error) {
 	// 1. Cek refresh token di DB
 	rt, err := s.authRepo.FindRefreshTokenByToken(ctx, oldRefreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	user, err := s.userRepo.FindByID(ctx, rt.UserID)
+	// 2. Token yang sudah di-revoke dipakai lagi → indikasi token dicuri
+	if rt.IsRevoked {
+		s.handleRefreshTokenReuse(ctx, rt)
+		return nil, ErrRefreshTokenReused
+	}
 
-	// 2. Jika tidak ditemukan sebagai email → coba sebagai username
+	if time.Now().After(rt.ExpiresAt) {
+		return nil, errors.New("refresh token not found or invalid")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, rt.UserID)
 	if err != nil {
-		user, err = s.userRepo.FindByUsername(ctx, user.Username)
-		if err != nil {
-			return nil, fmt.Errorf("Token tidak valid")
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		user = nil
+	}
+
+	// user dihapus / dinonaktifkan → sesi tidak boleh diperpanjang
+	if err := CheckUserStatus(user); err != nil {
+		if revokeErr := s.revokeAllSessions(ctx, rt.UserID); revokeErr != nil {
+			log.Printf("❌ Gagal revoke sesi user_id=%d: %v", rt.UserID, revokeErr)
 		}
+		return nil, err
 	}
 
-	// 2. Generate access token baru
-	accessToken, err := utils.GenerateAccessToken(rt.UserID, user.IsAdmin)
+	// 3. Generate access token baru
+	roles, err := s.roleRepo.FindRoleNames(ctx, user.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 3. Rotate refresh token (optional tapi lebih aman)
-	newRefreshToken, err := utils.GenerateRefreshTokenJWT(rt.UserID)
+	accessToken, accessJTI, err := utils.GenerateAccessToken(user.ID, roles)
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. Revoke token lama
-	if err := s.authRepo.RevokeRefreshToken(ctx, oldRefreshToken); err != nil {
+	// 4. Rotate refresh token dalam family yang sama
+	newRefreshToken, err := utils.GenerateRefreshTokenJWT(rt.UserID)
+	if err != nil {
 		return nil, err
 	}
 
-	// 5. Simpan token baru
+	// label perangkat tetap ikut sesi awal kecuali client mengirim label baru
+	label := rt.DeviceLabel
+	if client.DeviceLabel != "" || label == "" {
+		label = deviceLabel(client)
+	}
+
 	newRT := &models.RefreshToken{
-		UserID:    rt.UserID,
-		Token:     newRefreshToken,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
+		UserID:      rt.UserID,
+		Token:       newRefreshToken,
+		FamilyID:    rt.FamilyID,
+		AccessJTI:   accessJTI,
+		ExpiresAt:   time.Now().Add(7 * 24 * time.Hour),
+		UserAgent:   client.UserAgent,
+		IPAddress:   client.IPAddress,
+		DeviceLabel: label,
 	}
-	if err := s.authRepo.CreateRefreshToken(ctx, newRT); err != nil {
+
+	// 5. Revoke token lama + simpan token baru dalam satu transaksi, supaya
+	// sesi tidak hilang jika penyimpanan token baru gagal
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// gagal revoke berarti token sudah dipakai request lain
+		if err := s.authRepo.RevokeRefreshToken(ctx, oldRefreshToken); err != nil {
+			return ErrRefreshTokenReused
+		}
+		return s.authRepo.CreateRefreshToken(ctx, newRT)
+	})
+	if err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			s.handleRefreshTokenReuse(ctx, rt)
+		}
 		return nil, err
 	}
 
@@ -191,12 +453,232 @@ func (s *authService) RefreshToken(ctx context.Context, oldRefreshToken string)
 	}, nil
 }
 
+// handleRefreshTokenReuse revoke seluruh family dari token yang dipakai ulang
+func (s *authService) handleRefreshTokenReuse(ctx context.Context, rt *models.RefreshToken) {
+	log.Printf("⚠️  Refresh token reuse terdeteksi: user_id=%d family_id=%q token_id=%d", rt.UserID, rt.FamilyID, rt.ID)
+
+	var err error
+	if rt.FamilyID == "" {
+		// token lama sebelum ada family, amankan semua sesi user
+		err = s.revokeAllSessions(ctx, rt.UserID)
+	} else {
+		err = s.revokeFamily(ctx, rt.FamilyID)
+	}
+
+	if err != nil {
+		log.Printf("❌ Gagal revoke family refresh token user_id=%d: %v", rt.UserID, err)
+	}
+}
+
 func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	rt, err := s.authRepo.FindRefreshTokenByToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
 	// Revoke token di DB
-	err := s.authRepo.RevokeRefreshToken(ctx, refreshToken)
+	err = s.authRepo.RevokeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	// access token pasangan refresh token ini ikut dicabut
+	return s.denyAccessTokens(ctx, []models.RefreshToken{*rt})
+}
+
+// LogoutAll revoke semua refresh token user (logout dari semua perangkat)
+func (s *authService) LogoutAll(ctx context.Context, userID int) error {
+	return s.revokeAllSessions(ctx, userID)
+}
+
+// ChangePassword ganti password user yang sedang login. Sesi yang dipakai
+// request ini tetap aktif, sesi lain di-logout.
+func (s *authService) ChangePassword(ctx context.Context, userID int, currentJTI, currentPassword, newPassword string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// salah password ikut dihitung supaya sesi curian tidak bisa dipakai menebak password
+	userKey := strconv.Itoa(user.ID)
+	if err := s.guard.check(ctx, userLockPolicy, userKey); err != nil {
+		return err
+	}
+	if !utils.CheckPasswordHash(currentPassword, user.Password) {
+		if lockErr := s.guard.fail(ctx, userLockPolicy, userKey); lockErr != nil {
+			return lockErr
+		}
+		return ErrWrongPassword
+	}
+
+	if currentPassword == newPassword {
+		return errors.New("password baru tidak boleh sama dengan password lama")
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return errors.New("gagal meng-hash password")
+	}
+
+	user.Password = hashedPassword
+	user.UpdatedAt = time.Now()
+	user.UpdatedBy = &userID
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.authRepo.UpdatePassword(ctx, user); err != nil {
+			return err
+		}
+
+		if err := s.revokeOtherSessions(ctx, userID, currentJTI); err != nil {
+			return err
+		}
+
+		queueSecurityAlert(ctx, s.outbox, userLocale(ctx, s.userRepo, user.ID), user.Email, user, securityEventPasswordChanged, "", "")
+		return nil
+	})
+}
+
+// notifyAccountLocked kirim peringatan saat percobaan gagal barusan membuat akun terkunci
+func (s *authService) notifyAccountLocked(ctx context.Context, user *models.User, err error, client dto.ClientInfo) {
+	var lockErr *LoginLockError
+	if !errors.As(err, &lockErr) || lockErr.LockedUntil == nil {
+		return
+	}
+
+	queueSecurityAlert(ctx, s.outbox, userLocale(ctx, s.userRepo, user.ID), user.Email, user, securityEventAccountLocked, "", client.IPAddress)
+}
+
+// ListSessions daftar sesi login aktif user
+func (s *authService) ListSessions(ctx context.Context, userID int) ([]dto.SessionResponse, error) {
+	tokens, err := s.authRepo.FindActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]dto.SessionResponse, 0, len(tokens))
+	for _, t := range tokens {
+		sessions = append(sessions, dto.SessionResponse{
+			ID:           t.ID,
+			DeviceLabel:  t.DeviceLabel,
+			UserAgent:    t.UserAgent,
+			IPAddress:    t.IPAddress,
+			LastActiveAt: t.CreatedAt,
+			ExpiresAt:    t.ExpiresAt,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession logout satu perangkat (seluruh family token sesi tsb)
+func (s *authService) RevokeSession(ctx context.Context, userID, sessionID int) error {
+	rt, err := s.authRepo.FindRefreshTokenByID(ctx, sessionID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+
+	if rt.FamilyID == "" {
+		if err := s.authRepo.RevokeRefreshTokenByID(ctx, rt.ID); err != nil {
+			return err
+		}
+		return s.denyAccessTokens(ctx, []models.RefreshToken{*rt})
+	}
+
+	return s.revokeFamily(ctx, rt.FamilyID)
+}
+
+// revokeAllSessions revoke semua refresh token user dan access token yang masih berlaku
+func (s *authService) revokeAllSessions(ctx context.Context, userID int) error {
+	tokens, err := s.authRepo.FindTokensIssuedSince(ctx, userID, time.Now().Add(-utils.AccessTokenTTL))
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if err := s.authRepo.RevokeAllRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.denyAccessTokens(ctx, tokens)
+}
+
+// revokeOtherSessions revoke semua sesi user kecuali sesi pemilik access token currentJTI
+func (s *authService) revokeOtherSessions(ctx context.Context, userID int, currentJTI string) error {
+	keepFamilyID := ""
+	current, err := s.authRepo.FindRefreshTokenByAccessJTI(ctx, userID, currentJTI)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if current != nil {
+		keepFamilyID = current.FamilyID
+	}
+
+	tokens, err := s.authRepo.FindTokensIssuedSince(ctx, userID, time.Now().Add(-utils.AccessTokenTTL))
+	if err != nil {
+		return err
+	}
+
+	others := make([]models.RefreshToken, 0, len(tokens))
+	for _, t := range tokens {
+		if t.AccessJTI == currentJTI || (keepFamilyID != "" && t.FamilyID == keepFamilyID) {
+			continue
+		}
+		others = append(others, t)
+	}
+
+	// family kosong (sesi lama) tidak bisa dibedakan, semua refresh token di-revoke
+	if keepFamilyID == "" {
+		err = s.authRepo.RevokeAllRefreshTokens(ctx, userID)
+	} else {
+		err = s.authRepo.RevokeOtherRefreshTokens(ctx, userID, keepFamilyID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.denyAccessTokens(ctx, others)
+}
+
+// revokeFamily revoke satu sesi (family) dan access token yang masih berlaku
+func (s *authService) revokeFamily(ctx context.Context, familyID string) error {
+	tokens, err := s.authRepo.FindFamilyTokensIssuedSince(ctx, familyID, time.Now().Add(-utils.AccessTokenTTL))
+	if err != nil {
+		return err
+	}
+
+	if err := s.authRepo.RevokeTokenFamily(ctx, familyID); err != nil {
+		return err
+	}
+
+	return s.denyAccessTokens(ctx, tokens)
+}
+
+// denyAccessTokens masukkan jti access token ke denylist sampai token tsb expired.
+// Di dalam UnitOfWork baru dijalankan setelah commit, supaya rollback tidak
+// menyisakan access token yang terlanjur ditolak.
+func (s *authService) denyAccessTokens(ctx context.Context, tokens []models.RefreshToken) error {
+	return repositories.AfterCommit(ctx, func(ctx context.Context) error {
+		for _, t := range tokens {
+			if t.AccessJTI == "" {
+				continue
+			}
+
+			expiresAt := t.CreatedAt.Add(utils.AccessTokenTTL)
+			if err := utils.CurrentTokenDenylist().Add(ctx, t.AccessJTI, expiresAt); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// deviceLabel pakai label dari client, fallback tebakan dari User-Agent
+func deviceLabel(client dto.ClientInfo) string {
+	if client.DeviceLabel != "" {
+		return client.DeviceLabel
+	}
+	return utils.DeviceLabelFromUserAgent(client.UserAgent)
 }
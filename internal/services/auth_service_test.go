@@ -0,0 +1,244 @@
+package services
+
+import (
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/models"
+	"mmgrapp/pkg/utils"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+var testClient = dto.ClientInfo{IPAddress: "10.0.0.1", UserAgent: "go-test"}
+
+// login login dengan password, gagal test jika error
+func (e *testEnv) login(username, password string) map[string]interface{} {
+	e.t.Helper()
+
+	res, err := e.auth.Login(e.ctx, username, password, testClient)
+	if err != nil {
+		e.t.Fatalf("Login %s: %v", username, err)
+	}
+	return res.(map[string]interface{})
+}
+
+func TestForgotPasswordSendsResetOTP(t *testing.T) {
+	e := newTestEnv(t)
+	e.createUser("alice", "password123")
+	e.mailer.Reset()
+
+	if err := e.auth.ForgotPassword(e.ctx, "alice@example.com"); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	code := e.lastOTP("alice@example.com")
+
+	if err := e.auth.ResetPassword(e.ctx, "alice@example.com", code, "newpassword1"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+
+	if _, err := e.auth.Login(e.ctx, "alice", "password123", testClient); err == nil {
+		t.Fatal("password lama masih bisa dipakai login")
+	}
+	e.login("alice", "newpassword1")
+
+	// OTP sekali pakai
+	if err := e.auth.ResetPassword(e.ctx, "alice@example.com", code, "another123"); !errors.Is(err, ErrOTPInvalid) {
+		t.Fatalf("OTP bekas: got %v, want ErrOTPInvalid", err)
+	}
+}
+
+func TestForgotPasswordUnknownEmailSendsNothing(t *testing.T) {
+	e := newTestEnv(t)
+
+	if err := e.auth.ForgotPassword(e.ctx, "nobody@example.com"); err == nil {
+		t.Fatal("email tidak terdaftar harus ditolak")
+	}
+
+	e.deliverEmails()
+	if n := len(e.mailer.Messages()); n != 0 {
+		t.Fatalf("terkirim %d email untuk email tidak terdaftar", n)
+	}
+}
+
+func TestResetPasswordRevokesSessions(t *testing.T) {
+	e := newTestEnv(t)
+	e.createUser("alice", "password123")
+	session := e.login("alice", "password123")
+
+	if err := e.auth.ForgotPassword(e.ctx, "alice@example.com"); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	if err := e.auth.ResetPassword(e.ctx, "alice@example.com", e.lastOTP("alice@example.com"), "newpassword1"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+
+	if _, err := e.auth.RefreshToken(e.ctx, session["refresh_token"].(string), testClient); err == nil {
+		t.Fatal("refresh token lama masih berlaku setelah reset password")
+	}
+
+	claims, err := utils.VerifyJWT(session["access_token"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if denied, _ := utils.CurrentTokenDenylist().Contains(e.ctx, claims.ID); !denied {
+		t.Fatal("access token lama belum masuk denylist")
+	}
+}
+
+func TestRefreshTokenStoredHashed(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+	refreshToken := e.login("alice", "password123")["refresh_token"].(string)
+
+	var stored []models.RefreshToken
+	e.db.Where("user_id = ?", user.ID).Find(&stored)
+	if len(stored) != 1 {
+		t.Fatalf("jumlah refresh token = %d, want 1", len(stored))
+	}
+	if stored[0].Token != utils.HashToken(refreshToken) {
+		t.Fatalf("token tersimpan %q, want digest SHA-256 dari token", stored[0].Token)
+	}
+
+	rotated, err := e.auth.RefreshToken(e.ctx, refreshToken, testClient)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	newRefreshToken := rotated["refresh_token"].(string)
+
+	var plaintext int64
+	e.db.Model(&models.RefreshToken{}).Where("token IN ?", []string{refreshToken, newRefreshToken}).Count(&plaintext)
+	if plaintext != 0 {
+		t.Fatal("refresh token tersimpan plaintext")
+	}
+
+	// token lama dipakai ulang → seluruh family dicabut, termasuk token hasil rotasi
+	if _, err := e.auth.RefreshToken(e.ctx, refreshToken, testClient); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reuse token lama: got %v, want ErrRefreshTokenReused", err)
+	}
+	if _, err := e.auth.RefreshToken(e.ctx, newRefreshToken, testClient); err == nil {
+		t.Fatal("token hasil rotasi masih berlaku setelah reuse terdeteksi")
+	}
+}
+
+func TestRefreshTokenKeepsOldTokenWhenRotationFails(t *testing.T) {
+	e := newTestEnv(t)
+	e.createUser("alice", "password123")
+	refreshToken := e.login("alice", "password123")["refresh_token"].(string)
+
+	// simpan token baru gagal → revoke token lama ikut dibatalkan
+	errInsert := errors.New("insert gagal")
+	e.db.Callback().Create().Before("gorm:create").Register("test:fail_refresh_token", func(db *gorm.DB) {
+		if db.Statement.Table == "refresh_tokens" {
+			db.AddError(errInsert)
+		}
+	})
+	if _, err := e.auth.RefreshToken(e.ctx, refreshToken, testClient); !errors.Is(err, errInsert) {
+		t.Fatalf("RefreshToken: got %v, want %v", err, errInsert)
+	}
+	e.db.Callback().Create().Remove("test:fail_refresh_token")
+
+	rt, err := e.authRepo.FindRefreshTokenByToken(e.ctx, refreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt.IsRevoked {
+		t.Fatal("refresh token lama ter-revoke padahal rotasi gagal")
+	}
+	if _, err := e.auth.RefreshToken(e.ctx, refreshToken, testClient); err != nil {
+		t.Fatalf("RefreshToken setelah rotasi gagal: %v", err)
+	}
+}
+
+func TestLogoutRevokesRefreshTokenByDigest(t *testing.T) {
+	e := newTestEnv(t)
+	e.createUser("alice", "password123")
+	refreshToken := e.login("alice", "password123")["refresh_token"].(string)
+
+	if err := e.auth.Logout(e.ctx, refreshToken); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+
+	rt, err := e.authRepo.FindRefreshTokenByToken(e.ctx, refreshToken)
+	if err != nil {
+		t.Fatalf("FindRefreshTokenByToken: %v", err)
+	}
+	if !rt.IsRevoked {
+		t.Fatal("refresh token belum di-revoke setelah logout")
+	}
+}
+
+// accessJTI jti dari access token sebuah sesi login
+func (e *testEnv) accessJTI(session map[string]interface{}) string {
+	e.t.Helper()
+
+	claims, err := utils.VerifyJWT(session["access_token"].(string))
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return claims.ID
+}
+
+// denied apakah jti sudah masuk denylist
+func (e *testEnv) denied(jti string) bool {
+	e.t.Helper()
+
+	ok, err := utils.CurrentTokenDenylist().Contains(e.ctx, jti)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return ok
+}
+
+func TestLogoutDeniesOnlyThatSession(t *testing.T) {
+	e := newTestEnv(t)
+	e.createUser("alice", "password123")
+	phone := e.login("alice", "password123")
+	laptop := e.login("alice", "password123")
+
+	if err := e.auth.Logout(e.ctx, phone["refresh_token"].(string)); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+
+	if !e.denied(e.accessJTI(phone)) {
+		t.Fatal("access token sesi yang logout belum dicabut")
+	}
+	if e.denied(e.accessJTI(laptop)) {
+		t.Fatal("access token sesi lain ikut dicabut")
+	}
+}
+
+func TestLogoutAllDeniesEveryAccessToken(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+	phone := e.login("alice", "password123")
+	laptop := e.login("alice", "password123")
+
+	if err := e.auth.LogoutAll(e.ctx, user.ID); err != nil {
+		t.Fatalf("LogoutAll: %v", err)
+	}
+
+	for _, session := range []map[string]interface{}{phone, laptop} {
+		if !e.denied(e.accessJTI(session)) {
+			t.Fatal("access token belum dicabut setelah logout semua perangkat")
+		}
+	}
+}
+
+func TestDeactivateUserDeniesAccessTokens(t *testing.T) {
+	e := newTestEnv(t)
+	admin := e.createUser("admin", "password123")
+	user := e.createUser("alice", "password123")
+	session := e.login("alice", "password123")
+
+	if _, err := e.admin.SetActive(e.ctx, admin.ID, user.ID, false); err != nil {
+		t.Fatalf("SetActive: %v", err)
+	}
+
+	if !e.denied(e.accessJTI(session)) {
+		t.Fatal("access token user nonaktif belum dicabut")
+	}
+	if _, err := e.auth.RefreshToken(e.ctx, session["refresh_token"].(string), testClient); err == nil {
+		t.Fatal("user nonaktif masih bisa refresh token")
+	}
+}
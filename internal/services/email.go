@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"log"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/pkg/utils"
+	"time"
+)
+
+// Event untuk email peringatan keamanan
+const (
+	securityEventPasswordChanged = "password_changed"
+	securityEventEmailChanged    = "email_changed"
+	securityEventAccountLocked   = "account_locked"
+)
+
+type otpEmailData struct {
+	Username       string
+	Code           string
+	ExpiresMinutes int
+}
+
+type securityAlertData struct {
+	Username  string
+	Event     string
+	Detail    string
+	Time      string
+	IPAddress string
+}
+
+// userLocale bahasa email user, fallback ke DefaultLocale jika gagal dibaca
+func userLocale(ctx context.Context, userRepo repositories.UserRepository, userID int) string {
+	locale, err := userRepo.FindLocale(ctx, userID)
+	if err != nil {
+		log.Printf("⚠️  Gagal membaca locale user_id=%d: %v", userID, err)
+	}
+	return utils.NormalizeLocale(locale)
+}
+
+// newOTPEmail render email OTP sesuai purpose jadi baris outbox untuk toEmail
+func newOTPEmail(locale, purpose, toEmail string, user *models.User, otp string, ttl time.Duration) (*models.EmailOutbox, error) {
+	msg, err := utils.RenderEmail(locale, purpose, otpEmailData{
+		Username:       user.Username,
+		Code:           otp,
+		ExpiresMinutes: int(ttl.Minutes()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// OTP yang sudah kadaluarsa tidak ada gunanya dikirim
+	expiresAt := time.Now().Add(ttl)
+	email := newOutboxEmail(purpose, toEmail, msg)
+	email.ExpiresAt = &expiresAt
+
+	return email, nil
+}
+
+// queueSecurityAlert antre peringatan aktivitas akun. Gagal antre hanya
+// dicatat, tidak membatalkan aksi yang sudah berhasil.
+func queueSecurityAlert(ctx context.Context, outboxRepo repositories.EmailOutboxRepository, locale, toEmail string, user *models.User, event, detail, ipAddress string) {
+	msg, err := utils.RenderEmail(locale, "security_alert", securityAlertData{
+		Username:  user.Username,
+		Event:     event,
+		Detail:    detail,
+		Time:      time.Now().Format("02 Jan 2006 15:04 MST"),
+		IPAddress: ipAddress,
+	})
+	if err == nil {
+		err = outboxRepo.Create(ctx, newOutboxEmail("security_alert", toEmail, msg))
+	}
+
+	if err != nil {
+		log.Printf("❌ Gagal antre peringatan keamanan %s untuk user_id=%d: %v", event, user.ID, err)
+	}
+}
+
+func newOutboxEmail(template, toEmail string, msg utils.EmailMessage) *models.EmailOutbox {
+	return &models.EmailOutbox{
+		ToEmail:       toEmail,
+		Subject:       msg.Subject,
+		Template:      template,
+		TextBody:      msg.TextBody,
+		HTMLBody:      msg.HTMLBody,
+		Status:        models.EmailStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmailNotFound = errors.New("email tidak ditemukan")
+	ErrEmailNotDead  = errors.New("hanya email yang gagal terkirim (dead) yang bisa dikirim ulang")
+	ErrEmailExpired  = errors.New("email sudah kadaluarsa dan tidak bisa dikirim ulang")
+)
+
+// EmailOutboxService pemantauan outbox email untuk admin
+type EmailOutboxService interface {
+	List(ctx context.Context, filter repositories.EmailOutboxFilter) ([]models.EmailOutbox, *dto.PaginationMeta, error)
+	GetByID(ctx context.Context, id int) (*models.EmailOutbox, error)
+	Retry(ctx context.Context, id int) (*models.EmailOutbox, error)
+}
+
+type emailOutboxService struct {
+	repo repositories.EmailOutboxRepository
+}
+
+func NewEmailOutboxService(repo repositories.EmailOutboxRepository) EmailOutboxService {
+	return &emailOutboxService{repo: repo}
+}
+
+func (s *emailOutboxService) List(ctx context.Context, filter repositories.EmailOutboxFilter) ([]models.EmailOutbox, *dto.PaginationMeta, error) {
+	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
+
+	emails, total, err := s.repo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return emails, dto.NewPaginationMeta(filter.Page, filter.Limit, total), nil
+}
+
+func (s *emailOutboxService) GetByID(ctx context.Context, id int) (*models.EmailOutbox, error) {
+	email, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEmailNotFound
+		}
+		return nil, err
+	}
+
+	return email, nil
+}
+
+// Retry antre ulang email dead, akan diambil worker di putaran berikutnya
+func (s *emailOutboxService) Retry(ctx context.Context, id int) (*models.EmailOutbox, error) {
+	email, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// OTP kadaluarsa, user perlu minta OTP baru
+	if email.ExpiresAt != nil && !time.Now().Before(*email.ExpiresAt) {
+		return nil, ErrEmailExpired
+	}
+
+	if err := s.repo.Requeue(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEmailNotDead
+		}
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var ErrExpenseNotFound = errors.New("data pengeluaran tidak ditemukan")
+
+type ExpenseService interface {
+	Create(ctx context.Context, userID int, req dto.ExpenseRequest) (*models.Expense, error)
+	List(ctx context.Context, userID int, filter repositories.ExpenseFilter) ([]models.Expense, *dto.PaginationMeta, error)
+	GetByID(ctx context.Context, userID, id int) (*models.Expense, error)
+	Update(ctx context.Context, userID, id int, req dto.ExpenseRequest) (*models.Expense, error)
+	Delete(ctx context.Context, userID, id int) error
+}
+
+type expenseService struct {
+	repo        repositories.ExpenseRepository
+	accountRepo repositories.AccountRepository
+	periodRepo  repositories.PeriodRepository
+}
+
+func NewExpenseService(expenseRepo repositories.ExpenseRepository, accountRepo repositories.AccountRepository, periodRepo repositories.PeriodRepository) ExpenseService {
+	return &expenseService{
+		repo:        expenseRepo,
+		accountRepo: accountRepo,
+		periodRepo:  periodRepo,
+	}
+}
+
+func (s *expenseService) Create(ctx context.Context, userID int, req dto.ExpenseRequest) (*models.Expense, error) {
+	date, err := utils.ParseDate(req.Date)
+	if err != nil {
+		return nil, errors.New("format tanggal harus YYYY-MM-DD")
+	}
+
+	if err := validateAccountAndPeriod(ctx, s.accountRepo, s.periodRepo, userID, req.AccountID, req.PeriodID); err != nil {
+		return nil, err
+	}
+
+	expense := &models.Expense{
+		UserID:      userID,
+		PeriodID:    req.PeriodID,
+		AccountID:   req.AccountID,
+		Date:        date,
+		Category:    req.Category,
+		Description: req.Description,
+		Amount:      req.Amount,
+		CreatedBy:   &userID,
+		UpdatedBy:   &userID,
+	}
+
+	if err := s.repo.Create(ctx, expense); err != nil {
+		return nil, err
+	}
+
+	return expense, nil
+}
+
+func (s *expenseService) List(ctx context.Context, userID int, filter repositories.ExpenseFilter) ([]models.Expense, *dto.PaginationMeta, error) {
+	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
+
+	expenses, total, err := s.repo.FindAll(ctx, userID, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return expenses, dto.NewPaginationMeta(filter.Page, filter.Limit, total), nil
+}
+
+func (s *expenseService) GetByID(ctx context.Context, userID, id int) (*models.Expense, error) {
+	expense, err := s.repo.FindByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExpenseNotFound
+		}
+		return nil, err
+	}
+
+	return expense, nil
+}
+
+func (s *expenseService) Update(ctx context.Context, userID, id int, req dto.ExpenseRequest) (*models.Expense, error) {
+	expense, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := utils.ParseDate(req.Date)
+	if err != nil {
+		return nil, errors.New("format tanggal harus YYYY-MM-DD")
+	}
+
+	if err := validateAccountAndPeriod(ctx, s.accountRepo, s.periodRepo, userID, req.AccountID, req.PeriodID); err != nil {
+		return nil, err
+	}
+
+	expense.PeriodID = req.PeriodID
+	expense.AccountID = req.AccountID
+	expense.Date = date
+	expense.Category = req.Category
+	expense.Description = req.Description
+	expense.Amount = req.Amount
+	expense.UpdatedBy = &userID
+
+	if err := s.repo.Update(ctx, expense); err != nil {
+		return nil, err
+	}
+
+	return expense, nil
+}
+
+func (s *expenseService) Delete(ctx context.Context, userID, id int) error {
+	err := s.repo.Delete(ctx, id, userID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrExpenseNotFound
+	}
+
+	return err
+}
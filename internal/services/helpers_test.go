@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"log"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/internal/workers"
+	"mmgrapp/pkg/utils"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestMain pasang JWT key sementara untuk semua test di package ini
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "mmgrapp-jwt")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, "test.pem"), data, 0o600); err != nil {
+		log.Fatal(err)
+	}
+	if err := utils.LoadJWTKeys(dir, "test"); err != nil {
+		log.Fatal(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// testEnv service asli di atas database sqlite sementara. Email dikirim worker
+// ke MemoryMailer lewat deliverEmails, sama seperti alur outbox di server.
+type testEnv struct {
+	t      *testing.T
+	ctx    context.Context
+	db     *gorm.DB
+	mailer *utils.MemoryMailer
+	worker *workers.EmailWorker
+
+	userRepo         repositories.UserRepository
+	authRepo         repositories.AuthRepository
+	otpRepo          repositories.OTPRepository
+	loginAttemptRepo repositories.LoginAttemptRepository
+	mfaRepo          repositories.MFARepository
+	outboxRepo       repositories.EmailOutboxRepository
+
+	otp   OTPService
+	users UserService
+	auth  AuthService
+	mfa   MFAService
+	admin AdminUserService
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_pragma=busy_timeout(5000)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("gagal membuka database test: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	err = db.AutoMigrate(
+		&models.User{},
+		&models.Profile{},
+		&models.Account{},
+		&models.Period{},
+		&models.Income{},
+		&models.Expense{},
+		&models.UserOTP{},
+		&models.RefreshToken{},
+		&models.LoginAttempt{},
+		&models.UserMFA{},
+		&models.MFARecoveryCode{},
+		&models.EmailOutbox{},
+		&models.Permission{},
+		&models.Role{},
+	)
+	if err != nil {
+		t.Fatalf("gagal migrasi database test: %v", err)
+	}
+
+	// denylist global, setiap test mulai dari kosong
+	utils.SetTokenDenylist(utils.NewMemoryDenylist())
+
+	e := &testEnv{
+		t:      t,
+		ctx:    context.Background(),
+		db:     db,
+		mailer: utils.NewMemoryMailer(),
+	}
+
+	uow := repositories.NewUnitOfWork(db)
+	e.userRepo = repositories.NewUserRepository(db)
+	e.authRepo = repositories.NewAuthRepository(db)
+	e.otpRepo = repositories.NewOTPRepository(db)
+	e.loginAttemptRepo = repositories.NewLoginAttemptRepository(db)
+	e.mfaRepo = repositories.NewMFARepository(db)
+	e.outboxRepo = repositories.NewEmailOutboxRepository(db)
+	roleRepo := repositories.NewRoleRepository(db)
+
+	periodService := NewPeriodService(repositories.NewPeriodRepository(db), uow)
+	e.otp = NewOTPService(e.otpRepo, e.userRepo)
+	e.users = NewUserService(e.userRepo, e.otp, periodService, e.loginAttemptRepo, e.outboxRepo, uow)
+	e.auth = NewAuthService(e.authRepo, e.userRepo, e.otp, roleRepo, e.mfaRepo, e.loginAttemptRepo, e.outboxRepo, uow)
+	e.mfa = NewMFAService(e.mfaRepo, e.userRepo, e.loginAttemptRepo, uow, "MoneyApp")
+	e.admin = NewAdminUserService(e.userRepo, e.auth, e.otp, uow)
+	e.worker = workers.NewEmailWorker(e.outboxRepo, e.mailer, workers.DefaultEmailWorkerConfig())
+
+	return e
+}
+
+// deliverEmails kirim semua email yang antre di outbox ke MemoryMailer
+func (e *testEnv) deliverEmails() {
+	for e.worker.ProcessBatch(e.ctx) > 0 {
+	}
+}
+
+var otpCodePattern = regexp.MustCompile(`\b\d{6}\b`)
+
+// lastOTP kode OTP di email terakhir untuk alamat to
+func (e *testEnv) lastOTP(to string) string {
+	e.t.Helper()
+	e.deliverEmails()
+
+	msg, ok := e.mailer.Last(to)
+	if !ok {
+		e.t.Fatalf("tidak ada email ke %s", to)
+	}
+
+	code := otpCodePattern.FindString(msg.TextBody)
+	if code == "" {
+		e.t.Fatalf("email %q ke %s tidak berisi OTP", msg.Subject, to)
+	}
+	return code
+}
+
+// createUser daftarkan user lewat Register lalu tandai terverifikasi,
+// email = username@example.com
+func (e *testEnv) createUser(username, password string) *models.User {
+	e.t.Helper()
+
+	email := username + "@example.com"
+	if _, err := e.users.Register(e.ctx, username, email, password); err != nil {
+		e.t.Fatalf("Register %s: %v", username, err)
+	}
+	e.deliverEmails()
+
+	user, err := e.userRepo.VerifyUser(e.ctx, email)
+	if err != nil {
+		e.t.Fatalf("VerifyUser %s: %v", username, err)
+	}
+	return user
+}
+
+// skipOTPCooldown mundurkan waktu OTP terakhir supaya OTP baru boleh diminta
+func (e *testEnv) skipOTPCooldown(userID int, purpose string) {
+	e.t.Helper()
+
+	err := e.db.Model(&models.UserOTP{}).
+		Where("user_id = ? AND purpose = ?", userID, purpose).
+		Update("created_at", time.Now().Add(-otpPolicies[purpose].ResendCooldown)).Error
+	if err != nil {
+		e.t.Fatal(err)
+	}
+}
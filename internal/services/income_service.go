@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var ErrIncomeNotFound = errors.New("data pemasukan tidak ditemukan")
+
+type IncomeService interface {
+	Create(ctx context.Context, userID int, req dto.IncomeRequest) (*models.Income, error)
+	List(ctx context.Context, userID int, filter repositories.IncomeFilter) ([]models.Income, *dto.PaginationMeta, error)
+	GetByID(ctx context.Context, userID, id int) (*models.Income, error)
+	Update(ctx context.Context, userID, id int, req dto.IncomeRequest) (*models.Income, error)
+	Delete(ctx context.Context, userID, id int) error
+}
+
+type incomeService struct {
+	repo        repositories.IncomeRepository
+	accountRepo repositories.AccountRepository
+	periodRepo  repositories.PeriodRepository
+}
+
+func NewIncomeService(incomeRepo repositories.IncomeRepository, accountRepo repositories.AccountRepository, periodRepo repositories.PeriodRepository) IncomeService {
+	return &incomeService{
+		repo:        incomeRepo,
+		accountRepo: accountRepo,
+		periodRepo:  periodRepo,
+	}
+}
+
+func (s *incomeService) Create(ctx context.Context, userID int, req dto.IncomeRequest) (*models.Income, error) {
+	date, err := utils.ParseDate(req.Date)
+	if err != nil {
+		return nil, errors.New("format tanggal harus YYYY-MM-DD")
+	}
+
+	if err := validateAccountAndPeriod(ctx, s.accountRepo, s.periodRepo, userID, req.AccountID, req.PeriodID); err != nil {
+		return nil, err
+	}
+
+	income := &models.Income{
+		UserID:      userID,
+		PeriodID:    req.PeriodID,
+		AccountID:   req.AccountID,
+		Date:        date,
+		Category:    req.Category,
+		Description: req.Description,
+		Amount:      req.Amount,
+		CreatedBy:   &userID,
+		UpdatedBy:   &userID,
+	}
+
+	if err := s.repo.Create(ctx, income); err != nil {
+		return nil, err
+	}
+
+	return income, nil
+}
+
+func (s *incomeService) List(ctx context.Context, userID int, filter repositories.IncomeFilter) ([]models.Income, *dto.PaginationMeta, error) {
+	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
+
+	incomes, total, err := s.repo.FindAll(ctx, userID, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return incomes, dto.NewPaginationMeta(filter.Page, filter.Limit, total), nil
+}
+
+func (s *incomeService) GetByID(ctx context.Context, userID, id int) (*models.Income, error) {
+	income, err := s.repo.FindByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIncomeNotFound
+		}
+		return nil, err
+	}
+
+	return income, nil
+}
+
+func (s *incomeService) Update(ctx context.Context, userID, id int, req dto.IncomeRequest) (*models.Income, error) {
+	income, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := utils.ParseDate(req.Date)
+	if err != nil {
+		return nil, errors.New("format tanggal harus YYYY-MM-DD")
+	}
+
+	if err := validateAccountAndPeriod(ctx, s.accountRepo, s.periodRepo, userID, req.AccountID, req.PeriodID); err != nil {
+		return nil, err
+	}
+
+	income.PeriodID = req.PeriodID
+	income.AccountID = req.AccountID
+	income.Date = date
+	income.Category = req.Category
+	income.Description = req.Description
+	income.Amount = req.Amount
+	income.UpdatedBy = &userID
+
+	if err := s.repo.Update(ctx, income); err != nil {
+		return nil, err
+	}
+
+	return income, nil
+}
+
+func (s *incomeService) Delete(ctx context.Context, userID, id int) error {
+	err := s.repo.Delete(ctx, id, userID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrIncomeNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// loginFailureWindow hitungan gagal dimulai ulang jika tidak ada gagal baru selama ini
+const loginFailureWindow = 15 * time.Minute
+
+// lockPolicy aturan jeda bertahap & kunci untuk satu jenis key
+type lockPolicy struct {
+	prefix     string
+	lockCode   string
+	delayAfter int           // mulai gagal ke-n ada jeda bertahap, 0 = tanpa jeda
+	maxDelay   time.Duration // batas atas jeda bertahap
+	lockAfter  int           // gagal ke-n key dikunci
+	lockFor    time.Duration
+}
+
+var (
+	// per user: jeda 1s, 2s, 4s ... mulai gagal ke-3, kunci setelah 10x gagal
+	userLockPolicy = lockPolicy{
+		prefix:     models.LoginAttemptKeyUser,
+		lockCode:   "ACCOUNT_LOCKED",
+		delayAfter: 3,
+		maxDelay:   time.Minute,
+		lockAfter:  10,
+		lockFor:    30 * time.Minute,
+	}
+
+	// per IP: tanpa jeda (banyak user bisa berbagi IP), kunci setelah 50x gagal
+	ipLockPolicy = lockPolicy{
+		prefix:    models.LoginAttemptKeyIP,
+		lockCode:  "IP_LOCKED",
+		lockAfter: 50,
+		lockFor:   15 * time.Minute,
+	}
+)
+
+// LoginLockError login ditolak sementara karena terlalu banyak gagal
+type LoginLockError struct {
+	Code        string
+	Message     string
+	RetryAfter  time.Duration
+	LockedUntil *time.Time // terisi jika key dikunci, bukan sekadar jeda
+	Unlockable  bool       // kunci akun bisa dibuka lewat OTP email
+}
+
+func (e *LoginLockError) Error() string {
+	return e.Message
+}
+
+// loginGuard pencatat gagal login per user & per IP
+type loginGuard struct {
+	repo repositories.LoginAttemptRepository
+}
+
+// check tolak login jika key sedang dikunci atau masih dalam masa jeda
+func (g *loginGuard) check(ctx context.Context, policy lockPolicy, id string) error {
+	attempt, err := g.repo.FindByKey(ctx, policy.prefix+id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return policy.evaluate(attempt, time.Now())
+}
+
+// fail catat gagal login, kunci key jika sudah melewati batas. Error yang
+// dikembalikan berupa *LoginLockError jika percobaan berikutnya tertahan.
+func (g *loginGuard) fail(ctx context.Context, policy lockPolicy, id string) error {
+	now := time.Now()
+	key := policy.prefix + id
+
+	attempt, err := g.repo.RegisterFailure(ctx, key, now, loginFailureWindow)
+	if err != nil {
+		return err
+	}
+
+	if attempt.FailedCount >= policy.lockAfter {
+		lockedUntil := now.Add(policy.lockFor)
+		if err := g.repo.Lock(ctx, key, lockedUntil); err != nil {
+			return err
+		}
+		attempt.LockedUntil = &lockedUntil
+	}
+
+	return policy.evaluate(attempt, now)
+}
+
+// reset hapus hitungan gagal (login berhasil / akun dibuka)
+func (g *loginGuard) reset(ctx context.Context, policy lockPolicy, id string) error {
+	return g.repo.Reset(ctx, policy.prefix+id)
+}
+
+func (p lockPolicy) evaluate(attempt *models.LoginAttempt, now time.Time) error {
+	if attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil) {
+		retryAfter := attempt.LockedUntil.Sub(now)
+		return &LoginLockError{
+			Code:        p.lockCode,
+			Message:     fmt.Sprintf("terlalu banyak percobaan login gagal, coba lagi dalam %d menit", int(math.Ceil(retryAfter.Minutes()))),
+			RetryAfter:  retryAfter,
+			LockedUntil: attempt.LockedUntil,
+			Unlockable:  p.prefix == models.LoginAttemptKeyUser,
+		}
+	}
+
+	if p.delayAfter == 0 || attempt.FailedCount < p.delayAfter || now.Sub(attempt.LastFailedAt) > loginFailureWindow {
+		return nil
+	}
+
+	nextAllowed := attempt.LastFailedAt.Add(p.delay(attempt.FailedCount))
+	if !now.Before(nextAllowed) {
+		return nil
+	}
+
+	retryAfter := nextAllowed.Sub(now)
+	return &LoginLockError{
+		Code:       "LOGIN_THROTTLED",
+		Message:    fmt.Sprintf("terlalu banyak percobaan login gagal, coba lagi dalam %d detik", int(math.Ceil(retryAfter.Seconds()))),
+		RetryAfter: retryAfter,
+	}
+}
+
+// delay jeda eksponensial: 1s untuk gagal ke-delayAfter, lalu dobel tiap gagal
+func (p lockPolicy) delay(failedCount int) time.Duration {
+	shift := failedCount - p.delayAfter
+	if shift >= 16 {
+		return p.maxDelay
+	}
+
+	delay := time.Second << shift
+	if delay > p.maxDelay {
+		return p.maxDelay
+	}
+	return delay
+}
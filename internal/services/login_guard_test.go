@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"mmgrapp/internal/models"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLockPolicyDelay(t *testing.T) {
+	tests := []struct {
+		failedCount int
+		want        time.Duration
+	}{
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{6, 8 * time.Second},
+		{9, userLockPolicy.maxDelay},
+		{40, userLockPolicy.maxDelay},
+	}
+	for _, tt := range tests {
+		if got := userLockPolicy.delay(tt.failedCount); got != tt.want {
+			t.Errorf("delay(%d) = %v, want %v", tt.failedCount, got, tt.want)
+		}
+	}
+}
+
+func TestLockPolicyEvaluate(t *testing.T) {
+	now := time.Now()
+	lockedUntil := now.Add(10 * time.Minute)
+
+	tests := []struct {
+		name     string
+		policy   lockPolicy
+		attempt  models.LoginAttempt
+		wantCode string // kosong = boleh login
+	}{
+		{"di bawah batas jeda", userLockPolicy, models.LoginAttempt{FailedCount: 2, LastFailedAt: now}, ""},
+		{"masih jeda", userLockPolicy, models.LoginAttempt{FailedCount: 3, LastFailedAt: now}, "LOGIN_THROTTLED"},
+		{"jeda sudah lewat", userLockPolicy, models.LoginAttempt{FailedCount: 3, LastFailedAt: now.Add(-2 * time.Second)}, ""},
+		{"gagal lama di luar window", userLockPolicy, models.LoginAttempt{FailedCount: 9, LastFailedAt: now.Add(-loginFailureWindow - time.Minute)}, ""},
+		{"terkunci", userLockPolicy, models.LoginAttempt{FailedCount: 10, LastFailedAt: now, LockedUntil: &lockedUntil}, "ACCOUNT_LOCKED"},
+		{"IP tanpa jeda", ipLockPolicy, models.LoginAttempt{FailedCount: 30, LastFailedAt: now}, ""},
+		{"IP terkunci", ipLockPolicy, models.LoginAttempt{FailedCount: 50, LastFailedAt: now, LockedUntil: &lockedUntil}, "IP_LOCKED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.evaluate(&tt.attempt, now)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("got %v, want nil", err)
+				}
+				return
+			}
+
+			var lockErr *LoginLockError
+			if !errors.As(err, &lockErr) || lockErr.Code != tt.wantCode {
+				t.Fatalf("got %v, want LoginLockError %s", err, tt.wantCode)
+			}
+			if lockErr.RetryAfter <= 0 {
+				t.Fatalf("RetryAfter = %v, want > 0", lockErr.RetryAfter)
+			}
+		})
+	}
+}
+
+// failLogin login dengan password salah sampai n kali gagal tercatat
+func (e *testEnv) failLogin(username string, n int) error {
+	e.t.Helper()
+
+	var err error
+	for i := 0; i < n; i++ {
+		_, err = e.auth.Login(e.ctx, username, "wrong-password", testClient)
+		if err == nil {
+			e.t.Fatal("login dengan password salah berhasil")
+		}
+	}
+	return err
+}
+
+// backdateFailures mundurkan gagal terakhir supaya jeda bertahap sudah lewat
+func (e *testEnv) backdateFailures(userID int) {
+	e.t.Helper()
+
+	err := e.db.Model(&models.LoginAttempt{}).
+		Where("attempt_key = ?", userLockPolicy.prefix+strconv.Itoa(userID)).
+		Update("last_failed_at", time.Now().Add(-2*userLockPolicy.maxDelay)).Error
+	if err != nil {
+		e.t.Fatal(err)
+	}
+}
+
+func TestLoginThrottlesAfterRepeatedFailures(t *testing.T) {
+	e := newTestEnv(t)
+	e.createUser("alice", "password123")
+
+	// gagal ke-3 mulai kena jeda
+	err := e.failLogin("alice", userLockPolicy.delayAfter)
+	var lockErr *LoginLockError
+	if !errors.As(err, &lockErr) || lockErr.Code != "LOGIN_THROTTLED" {
+		t.Fatalf("gagal ke-%d: got %v, want LOGIN_THROTTLED", userLockPolicy.delayAfter, err)
+	}
+
+	// selama jeda password benar pun ditolak
+	if _, err := e.auth.Login(e.ctx, "alice", "password123", testClient); !errors.As(err, &lockErr) {
+		t.Fatalf("login saat jeda: got %v, want LoginLockError", err)
+	}
+}
+
+func TestLoginLocksAccountAndUnlocksWithOTP(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+	e.mailer.Reset()
+
+	var err error
+	for i := 0; i < userLockPolicy.lockAfter; i++ {
+		e.backdateFailures(user.ID)
+		err = e.failLogin("alice", 1)
+	}
+
+	var lockErr *LoginLockError
+	if !errors.As(err, &lockErr) || lockErr.Code != "ACCOUNT_LOCKED" {
+		t.Fatalf("gagal ke-%d: got %v, want ACCOUNT_LOCKED", userLockPolicy.lockAfter, err)
+	}
+	if lockErr.LockedUntil == nil || !lockErr.Unlockable {
+		t.Fatalf("lock error = %+v, want LockedUntil terisi & Unlockable", lockErr)
+	}
+
+	// pemilik akun diberi tahu
+	e.deliverEmails()
+	if _, ok := e.mailer.Last("alice@example.com"); !ok {
+		t.Fatal("tidak ada email peringatan akun terkunci")
+	}
+
+	// jeda dilewati pun akun tetap terkunci
+	e.backdateFailures(user.ID)
+	if _, err := e.auth.Login(e.ctx, "alice", "password123", testClient); !errors.As(err, &lockErr) || lockErr.Code != "ACCOUNT_LOCKED" {
+		t.Fatalf("login saat terkunci: got %v, want ACCOUNT_LOCKED", err)
+	}
+
+	if err := e.auth.RequestUnlock(e.ctx, "alice@example.com"); err != nil {
+		t.Fatalf("RequestUnlock: %v", err)
+	}
+	if err := e.auth.UnlockAccount(e.ctx, "alice@example.com", e.lastOTP("alice@example.com")); err != nil {
+		t.Fatalf("UnlockAccount: %v", err)
+	}
+
+	e.login("alice", "password123")
+}
+
+func TestRequestUnlockRejectsUnlockedAccount(t *testing.T) {
+	e := newTestEnv(t)
+	e.createUser("alice", "password123")
+	e.mailer.Reset()
+
+	if err := e.auth.RequestUnlock(e.ctx, "alice@example.com"); !errors.Is(err, ErrAccountNotLocked) {
+		t.Fatalf("got %v, want ErrAccountNotLocked", err)
+	}
+
+	e.deliverEmails()
+	if n := len(e.mailer.Messages()); n != 0 {
+		t.Fatalf("terkirim %d email untuk akun yang tidak terkunci", n)
+	}
+}
+
+func TestLoginSuccessResetsFailures(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+
+	e.failLogin("alice", userLockPolicy.delayAfter-1)
+	e.login("alice", "password123")
+
+	var count int64
+	e.db.Model(&models.LoginAttempt{}).Where("attempt_key = ?", userLockPolicy.prefix+strconv.Itoa(user.ID)).Count(&count)
+	if count != 0 {
+		t.Fatal("hitungan gagal tidak direset setelah login berhasil")
+	}
+}
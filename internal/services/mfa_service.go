@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// recoveryCodeCount jumlah recovery code per generate
+const recoveryCodeCount = 10
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("MFA sudah aktif")
+	ErrMFANotEnabled     = errors.New("MFA belum aktif")
+	ErrMFASetupNotFound  = errors.New("setup MFA belum dimulai")
+	ErrInvalidMFACode    = errors.New("kode MFA salah atau sudah dipakai")
+)
+
+type MFAService interface {
+	Status(ctx context.Context, userID int) (*dto.MFAStatusResponse, error)
+	Setup(ctx context.Context, userID int) (*dto.MFASetupResponse, error)
+	Enable(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID int, password, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error)
+}
+
+type mfaService struct {
+	mfaRepo  repositories.MFARepository
+	userRepo repositories.UserRepository
+	guard    *loginGuard
+	uow      repositories.UnitOfWork
+	issuer   string // nama aplikasi yang tampil di authenticator
+}
+
+func NewMFAService(mfaRepo repositories.MFARepository, userRepo repositories.UserRepository, loginAttemptRepo repositories.LoginAttemptRepository, uow repositories.UnitOfWork, issuer string) MFAService {
+	return &mfaService{
+		mfaRepo:  mfaRepo,
+		userRepo: userRepo,
+		guard:    &loginGuard{repo: loginAttemptRepo},
+		uow:      uow,
+		issuer:   issuer,
+	}
+}
+
+func (s *mfaService) Status(ctx context.Context, userID int) (*dto.MFAStatusResponse, error) {
+	mfa, err := s.findEnabled(ctx, userID)
+	if errors.Is(err, ErrMFANotEnabled) {
+		return &dto.MFAStatusResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, err := s.mfaRepo.CountUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MFAStatusResponse{
+		Enabled:                true,
+		EnabledAt:              mfa.EnabledAt,
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// Setup buat secret baru (belum aktif sampai dikonfirmasi lewat Enable)
+func (s *mfaService) Setup(ctx context.Context, userID int) (*dto.MFASetupResponse, error) {
+	if _, err := s.findEnabled(ctx, userID); err == nil {
+		return nil, ErrMFAAlreadyEnabled
+	} else if !errors.Is(err, ErrMFANotEnabled) {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.SavePending(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &dto.MFASetupResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Enable konfirmasi setup dengan kode pertama dari authenticator
+func (s *mfaService) Enable(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error) {
+	mfa, err := s.mfaRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMFASetupNotFound
+		}
+		return nil, err
+	}
+	if mfa.EnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	step, ok := utils.ValidateTOTP(mfa.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	// MFA aktif hanya bersama recovery code-nya
+	var codes *dto.MFARecoveryCodesResponse
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.mfaRepo.Enable(ctx, userID, step); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMFAAlreadyEnabled
+			}
+			return err
+		}
+
+		codes, err = s.replaceRecoveryCodes(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable matikan MFA, wajib password + kode MFA
+func (s *mfaService) Disable(ctx context.Context, userID int, password, code string) error {
+	mfa, err := s.findEnabled(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// salah password / kode ikut dihitung supaya sesi curian tidak bisa menebak
+	userKey := strconv.Itoa(userID)
+	if err := s.guard.check(ctx, userLockPolicy, userKey); err != nil {
+		return err
+	}
+	if !utils.CheckPasswordHash(password, user.Password) {
+		return s.fail(ctx, userKey, ErrWrongPassword)
+	}
+
+	if err := verifyMFACode(ctx, s.mfaRepo, mfa, code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			return s.fail(ctx, userKey, err)
+		}
+		return err
+	}
+
+	return s.mfaRepo.Delete(ctx, userID)
+}
+
+// RegenerateRecoveryCodes ganti semua recovery code, code lama langsung tidak berlaku
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*dto.MFARecoveryCodesResponse, error) {
+	mfa, err := s.findEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	userKey := strconv.Itoa(userID)
+	if err := s.guard.check(ctx, userLockPolicy, userKey); err != nil {
+		return nil, err
+	}
+	if err := verifyMFACode(ctx, s.mfaRepo, mfa, code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			return nil, s.fail(ctx, userKey, err)
+		}
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+// fail catat percobaan gagal, *LoginLockError jika akun jadi tertahan, selain itu err
+func (s *mfaService) fail(ctx context.Context, userKey string, err error) error {
+	if lockErr := s.guard.fail(ctx, userLockPolicy, userKey); lockErr != nil {
+		return lockErr
+	}
+	return err
+}
+
+func (s *mfaService) findEnabled(ctx context.Context, userID int) (*models.UserMFA, error) {
+	return findEnabledMFA(ctx, s.mfaRepo, userID)
+}
+
+func (s *mfaService) replaceRecoveryCodes(ctx context.Context, userID int) (*dto.MFARecoveryCodesResponse, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, codes); err != nil {
+		return nil, err
+	}
+
+	return &dto.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// findEnabledMFA MFA user yang sudah aktif, ErrMFANotEnabled jika belum
+func findEnabledMFA(ctx context.Context, repo repositories.MFARepository, userID int) (*models.UserMFA, error) {
+	mfa, err := repo.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMFANotEnabled
+		}
+		return nil, err
+	}
+	if mfa.EnabledAt == nil {
+		return nil, ErrMFANotEnabled
+	}
+
+	return mfa, nil
+}
+
+// verifyMFACode terima kode TOTP 6 digit atau recovery code. Kode TOTP yang
+// sudah dipakai (step sama) dan recovery code bekas ditolak.
+func verifyMFACode(ctx context.Context, repo repositories.MFARepository, mfa *models.UserMFA, code string) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) == utils.TOTPDigits {
+		step, ok := utils.ValidateTOTP(mfa.Secret, code, time.Now())
+		if !ok {
+			return ErrInvalidMFACode
+		}
+
+		fresh, err := repo.MarkStepUsed(ctx, mfa.UserID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	used, err := repo.UseRecoveryCode(ctx, mfa.UserID, utils.NormalizeRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
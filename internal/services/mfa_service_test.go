@@ -0,0 +1,279 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"mmgrapp/internal/models"
+	"mmgrapp/pkg/utils"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// totpCode kode TOTP untuk langkah step, seperti yang dihitung aplikasi authenticator
+func totpCode(t *testing.T, secret string, step int64) string {
+	t.Helper()
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000)
+}
+
+// enableMFA aktifkan MFA user dengan kode langkah step, mengembalikan secret & recovery code.
+// Test hanya memakai langkah sekarang dan berikutnya supaya tetap valid walau
+// pergantian langkah 30 detik terjadi di tengah test.
+func (e *testEnv) enableMFA(userID int, step int64) (string, []string) {
+	e.t.Helper()
+
+	setup, err := e.mfa.Setup(e.ctx, userID)
+	if err != nil {
+		e.t.Fatalf("Setup: %v", err)
+	}
+
+	codes, err := e.mfa.Enable(e.ctx, userID, totpCode(e.t, setup.Secret, step))
+	if err != nil {
+		e.t.Fatalf("Enable: %v", err)
+	}
+	return setup.Secret, codes.RecoveryCodes
+}
+
+// mfaChallenge login dengan password yang berhenti di tantangan MFA
+func (e *testEnv) mfaChallenge(username, password string) string {
+	e.t.Helper()
+
+	res := e.login(username, password)
+	if res["mfa_required"] != true {
+		e.t.Fatalf("login tanpa tantangan MFA: %v", res)
+	}
+	if _, ok := res["access_token"]; ok {
+		e.t.Fatal("access token terbit sebelum kode MFA diverifikasi")
+	}
+	return res["mfa_token"].(string)
+}
+
+func TestMFAEnrollment(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+	step := utils.TOTPStep(time.Now())
+
+	setup, err := e.mfa.Setup(e.ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	if !strings.HasPrefix(setup.ProvisioningURI, "otpauth://totp/") || !strings.Contains(setup.ProvisioningURI, setup.Secret) {
+		t.Fatalf("provisioning URI tidak valid: %s", setup.ProvisioningURI)
+	}
+
+	// belum dikonfirmasi → login masih tanpa MFA
+	if res := e.login("alice", "password123"); res["access_token"] == nil {
+		t.Fatal("MFA yang belum aktif sudah diminta saat login")
+	}
+
+	if _, err := e.mfa.Enable(e.ctx, user.ID, "000000"); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("Enable kode salah: got %v, want ErrInvalidMFACode", err)
+	}
+
+	codes, err := e.mfa.Enable(e.ctx, user.ID, totpCode(t, setup.Secret, step))
+	if err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	if len(codes.RecoveryCodes) != recoveryCodeCount {
+		t.Fatalf("jumlah recovery code = %d, want %d", len(codes.RecoveryCodes), recoveryCodeCount)
+	}
+
+	// recovery code tidak disimpan plaintext
+	var stored []models.MFARecoveryCode
+	e.db.Where("user_id = ?", user.ID).Find(&stored)
+	for _, row := range stored {
+		for _, code := range codes.RecoveryCodes {
+			if row.CodeHash == code {
+				t.Fatal("recovery code tersimpan plaintext")
+			}
+		}
+	}
+
+	status, err := e.mfa.Status(e.ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Enabled || status.RecoveryCodesRemaining != recoveryCodeCount {
+		t.Fatalf("status = %+v, want aktif dengan %d recovery code", status, recoveryCodeCount)
+	}
+}
+
+func TestMFALoginWithTOTP(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+	step := utils.TOTPStep(time.Now())
+	secret, _ := e.enableMFA(user.ID, step)
+
+	mfaToken := e.mfaChallenge("alice", "password123")
+
+	// kode yang sudah dipakai saat Enable tidak bisa dipakai ulang
+	if _, err := e.auth.VerifyMFA(e.ctx, mfaToken, totpCode(t, secret, step), testClient); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("replay kode Enable: got %v, want ErrInvalidMFACode", err)
+	}
+
+	res, err := e.auth.VerifyMFA(e.ctx, mfaToken, totpCode(t, secret, step+1), testClient)
+	if err != nil {
+		t.Fatalf("VerifyMFA: %v", err)
+	}
+	if res.(map[string]interface{})["access_token"] == nil {
+		t.Fatal("VerifyMFA tidak menerbitkan access token")
+	}
+
+	// token tantangan sekali pakai
+	if _, err := e.auth.VerifyMFA(e.ctx, mfaToken, totpCode(t, secret, step+1), testClient); !errors.Is(err, ErrInvalidMFAChallenge) {
+		t.Fatalf("token tantangan bekas: got %v, want ErrInvalidMFAChallenge", err)
+	}
+
+	// kode yang sama tidak bisa dipakai di login berikutnya
+	mfaToken = e.mfaChallenge("alice", "password123")
+	if _, err := e.auth.VerifyMFA(e.ctx, mfaToken, totpCode(t, secret, step+1), testClient); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("replay kode login: got %v, want ErrInvalidMFACode", err)
+	}
+}
+
+func TestMFALoginWithRecoveryCode(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+	_, recoveryCodes := e.enableMFA(user.ID, utils.TOTPStep(time.Now()))
+
+	mfaToken := e.mfaChallenge("alice", "password123")
+	if _, err := e.auth.VerifyMFA(e.ctx, mfaToken, strings.ToUpper(recoveryCodes[0]), testClient); err != nil {
+		t.Fatalf("VerifyMFA dengan recovery code: %v", err)
+	}
+
+	mfaToken = e.mfaChallenge("alice", "password123")
+	if _, err := e.auth.VerifyMFA(e.ctx, mfaToken, recoveryCodes[0], testClient); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("recovery code bekas: got %v, want ErrInvalidMFACode", err)
+	}
+
+	status, err := e.mfa.Status(e.ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.RecoveryCodesRemaining != recoveryCodeCount-1 {
+		t.Fatalf("sisa recovery code = %d, want %d", status.RecoveryCodesRemaining, recoveryCodeCount-1)
+	}
+}
+
+func TestMFAChallengeConcurrentUse(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+	_, recoveryCodes := e.enableMFA(user.ID, utils.TOTPStep(time.Now()))
+	mfaToken := e.mfaChallenge("alice", "password123")
+
+	// dua kode valid berbeda dengan token tantangan yang sama → hanya satu sesi
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = e.auth.VerifyMFA(e.ctx, mfaToken, recoveryCodes[i], testClient)
+		}(i)
+	}
+	wg.Wait()
+
+	var ok, rejected int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			ok++
+		case errors.Is(err, ErrInvalidMFAChallenge):
+			rejected++
+		default:
+			t.Fatalf("VerifyMFA: %v", err)
+		}
+	}
+	if ok != 1 || rejected != 1 {
+		t.Fatalf("berhasil = %d, ditolak = %d; want 1 dan 1", ok, rejected)
+	}
+}
+
+func TestMFADisableCountsFailures(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+	_, recoveryCodes := e.enableMFA(user.ID, utils.TOTPStep(time.Now()))
+
+	if err := e.mfa.Disable(e.ctx, user.ID, "wrong-password", recoveryCodes[0]); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("password salah: got %v, want ErrWrongPassword", err)
+	}
+	if err := e.mfa.Disable(e.ctx, user.ID, "password123", "aaaaa-aaaaa"); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("kode salah: got %v, want ErrInvalidMFACode", err)
+	}
+
+	// gagal ke-3 kena jeda, percobaan berikutnya ditolak sebelum password dicek
+	var lockErr *LoginLockError
+	if err := e.mfa.Disable(e.ctx, user.ID, "wrong-password", recoveryCodes[0]); !errors.As(err, &lockErr) {
+		t.Fatalf("gagal ke-3: got %v, want LoginLockError", err)
+	}
+	if err := e.mfa.Disable(e.ctx, user.ID, "password123", recoveryCodes[0]); !errors.As(err, &lockErr) {
+		t.Fatalf("saat jeda: got %v, want LoginLockError", err)
+	}
+
+	e.backdateFailures(user.ID)
+	if err := e.mfa.Disable(e.ctx, user.ID, "password123", recoveryCodes[0]); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+
+	if res := e.login("alice", "password123"); res["access_token"] == nil {
+		t.Fatal("login masih meminta MFA setelah dinonaktifkan")
+	}
+}
+
+func TestMFARegenerateRecoveryCodes(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+	_, oldCodes := e.enableMFA(user.ID, utils.TOTPStep(time.Now()))
+
+	for i := 1; i < userLockPolicy.delayAfter; i++ {
+		if _, err := e.mfa.RegenerateRecoveryCodes(e.ctx, user.ID, "aaaaa-aaaaa"); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("kode salah ke-%d: got %v, want ErrInvalidMFACode", i, err)
+		}
+	}
+	var lockErr *LoginLockError
+	if _, err := e.mfa.RegenerateRecoveryCodes(e.ctx, user.ID, "aaaaa-aaaaa"); !errors.As(err, &lockErr) {
+		t.Fatalf("kode salah ke-%d: got %v, want LoginLockError", userLockPolicy.delayAfter, err)
+	}
+
+	e.backdateFailures(user.ID)
+	codes, err := e.mfa.RegenerateRecoveryCodes(e.ctx, user.ID, oldCodes[0])
+	if err != nil {
+		t.Fatalf("RegenerateRecoveryCodes: %v", err)
+	}
+	if len(codes.RecoveryCodes) != recoveryCodeCount {
+		t.Fatalf("jumlah recovery code = %d, want %d", len(codes.RecoveryCodes), recoveryCodeCount)
+	}
+
+	// code lama langsung tidak berlaku. Hitungan gagal di atas dibuang dulu
+	// supaya penolakan berikut bukan karena jeda.
+	if err := e.loginAttemptRepo.Reset(e.ctx, userLockPolicy.prefix+strconv.Itoa(user.ID)); err != nil {
+		t.Fatal(err)
+	}
+	mfaToken := e.mfaChallenge("alice", "password123")
+	if _, err := e.auth.VerifyMFA(e.ctx, mfaToken, oldCodes[1], testClient); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("recovery code lama: got %v, want ErrInvalidMFACode", err)
+	}
+	if _, err := e.auth.VerifyMFA(e.ctx, mfaToken, codes.RecoveryCodes[0], testClient); err != nil {
+		t.Fatalf("VerifyMFA dengan recovery code baru: %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/pkg/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Purpose OTP yang dikenal sistem, sekaligus nama template email-nya
+const (
+	OTPPurposeEmailVerification = "email_verification"
+	OTPPurposePasswordReset     = "password_reset"
+	OTPPurposeEmailChange       = "email_change"
+	OTPPurposeAccountUnlock     = "account_unlock"
+)
+
+// otpPolicy aturan untuk satu purpose OTP
+type otpPolicy struct {
+	TTL            time.Duration // masa berlaku OTP
+	MaxAttempts    int           // batas salah input sebelum OTP dikunci
+	ResendCooldown time.Duration // jeda minimal sebelum boleh minta OTP baru
+	Resendable     bool          // boleh diminta ulang lewat endpoint resend-otp
+}
+
+// otpPolicies registry purpose OTP, purpose di luar daftar ini ditolak
+var otpPolicies = map[string]otpPolicy{
+	OTPPurposeEmailVerification: {TTL: 5 * time.Minute, MaxAttempts: 5, ResendCooldown: time.Minute, Resendable: true},
+	OTPPurposePasswordReset:     {TTL: 5 * time.Minute, MaxAttempts: 5, ResendCooldown: time.Minute, Resendable: true},
+	OTPPurposeEmailChange:       {TTL: 5 * time.Minute, MaxAttempts: 5, ResendCooldown: time.Minute},
+	OTPPurposeAccountUnlock:     {TTL: 5 * time.Minute, MaxAttempts: 5, ResendCooldown: time.Minute},
+}
+
+var (
+	ErrInvalidOTPPurpose  = errors.New("purpose OTP tidak valid")
+	ErrOTPInvalid         = errors.New("OTP tidak valid atau kadaluarsa")
+	ErrOTPIncorrect       = errors.New("OTP salah")
+	ErrOTPTooManyAttempts = errors.New("terlalu banyak percobaan OTP, silakan minta OTP baru")
+)
+
+// OTPCooldownError OTP baru diminta sebelum jeda purpose tersebut habis
+type OTPCooldownError struct {
+	RetryAfter time.Duration
+}
+
+func (e *OTPCooldownError) Error() string {
+	return fmt.Sprintf("tunggu %d detik sebelum meminta OTP baru", int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// OTPService satu-satunya pintu untuk membuat, mengirim & memverifikasi OTP
+type OTPService interface {
+	// Send buat OTP baru (menggantikan OTP lama untuk purpose yang sama) lalu
+	// antre email-nya. target kosong = email user, selain itu OTP dikirim ke
+	// target dan disimpan sebagai UserOTP.Target (mis. email baru).
+	Send(ctx context.Context, user *models.User, purpose, target string) error
+	// Issue sama seperti Send tanpa cek cooldown, untuk OTP yang dipicu admin
+	// (bukan permintaan user) sehingga tidak boleh ditolak karena OTP sebelumnya
+	Issue(ctx context.Context, user *models.User, purpose, target string) error
+	// Verify cek kode OTP & catat percobaannya. Panggil di luar transaksi supaya
+	// percobaan yang gagal tetap tercatat, lalu Consume di dalam transaksi aksi.
+	Verify(ctx context.Context, userID int, purpose, code string) (*models.UserOTP, error)
+	// Consume hapus OTP yang sudah dipakai, gagal jika sudah lebih dulu dipakai
+	Consume(ctx context.Context, otp *models.UserOTP) error
+	// Resendable apakah purpose boleh diminta ulang lewat endpoint publik
+	Resendable(purpose string) bool
+}
+
+type otpService struct {
+	repo     repositories.OTPRepository
+	userRepo repositories.UserRepository
+}
+
+func NewOTPService(otpRepo repositories.OTPRepository, userRepo repositories.UserRepository) OTPService {
+	return &otpService{
+		repo:     otpRepo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *otpService) Send(ctx context.Context, user *models.User, purpose, target string) error {
+	policy, ok := otpPolicies[purpose]
+	if !ok {
+		return ErrInvalidOTPPurpose
+	}
+
+	if err := s.checkCooldown(ctx, user.ID, purpose, policy); err != nil {
+		return err
+	}
+
+	return s.issue(ctx, user, purpose, target, policy)
+}
+
+func (s *otpService) Issue(ctx context.Context, user *models.User, purpose, target string) error {
+	policy, ok := otpPolicies[purpose]
+	if !ok {
+		return ErrInvalidOTPPurpose
+	}
+
+	return s.issue(ctx, user, purpose, target, policy)
+}
+
+func (s *otpService) issue(ctx context.Context, user *models.User, purpose, target string, policy otpPolicy) error {
+	otp, hashedOTP, err := utils.GenerateOTP()
+	if err != nil {
+		return errors.New("gagal generate OTP")
+	}
+
+	toEmail := user.Email
+	if target != "" {
+		toEmail = target
+	}
+
+	message, err := newOTPEmail(userLocale(ctx, s.userRepo, user.ID), purpose, toEmail, user, otp, policy.TTL)
+	if err != nil {
+		return err
+	}
+
+	// OTP & email-nya disimpan dalam satu transaksi
+	return s.repo.Replace(ctx, &models.UserOTP{
+		UserID:    user.ID,
+		OTP:       hashedOTP,
+		Purpose:   purpose,
+		Target:    target,
+		ExpiresAt: time.Now().Add(policy.TTL),
+	}, message)
+}
+
+func (s *otpService) Verify(ctx context.Context, userID int, purpose, code string) (*models.UserOTP, error) {
+	policy, ok := otpPolicies[purpose]
+	if !ok {
+		return nil, ErrInvalidOTPPurpose
+	}
+
+	storedOTP, err := s.repo.FindValidOTP(ctx, userID, purpose)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		utils.CheckOTP(code, "") // samakan waktu respon dengan OTP salah
+		return nil, ErrOTPInvalid
+	}
+
+	// catat percobaan sebelum compare, supaya tebakan paralel tetap terbatas
+	allowed, err := s.repo.RegisterAttempt(ctx, storedOTP.ID, policy.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrOTPTooManyAttempts
+	}
+
+	if !utils.CheckOTP(code, storedOTP.OTP) {
+		return nil, ErrOTPIncorrect
+	}
+
+	return storedOTP, nil
+}
+
+func (s *otpService) Consume(ctx context.Context, otp *models.UserOTP) error {
+	if err := s.repo.Consume(ctx, otp.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOTPInvalid
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *otpService) Resendable(purpose string) bool {
+	return otpPolicies[purpose].Resendable
+}
+
+// checkCooldown tolak permintaan OTP baru jika OTP terakhir belum lewat cooldown
+func (s *otpService) checkCooldown(ctx context.Context, userID int, purpose string, policy otpPolicy) error {
+	latest, err := s.repo.FindLatest(ctx, userID, purpose)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if wait := time.Until(latest.CreatedAt.Add(policy.ResendCooldown)); wait > 0 {
+		return &OTPCooldownError{RetryAfter: wait}
+	}
+
+	return nil
+}
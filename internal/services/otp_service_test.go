@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"mmgrapp/internal/models"
+	"testing"
+	"time"
+)
+
+func TestOTPSendRejectsUnknownPurpose(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+
+	if err := e.otp.Send(e.ctx, user, "login", ""); !errors.Is(err, ErrInvalidOTPPurpose) {
+		t.Fatalf("Send: got %v, want ErrInvalidOTPPurpose", err)
+	}
+	if _, err := e.otp.Verify(e.ctx, user.ID, "login", "123456"); !errors.Is(err, ErrInvalidOTPPurpose) {
+		t.Fatalf("Verify: got %v, want ErrInvalidOTPPurpose", err)
+	}
+}
+
+func TestOTPStoredHashedWithPolicyTTL(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+
+	if err := e.otp.Send(e.ctx, user, OTPPurposePasswordReset, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	code := e.lastOTP("alice@example.com")
+
+	var stored models.UserOTP
+	if err := e.db.Where("user_id = ? AND purpose = ?", user.ID, OTPPurposePasswordReset).First(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.OTP == code {
+		t.Fatal("OTP tersimpan plaintext")
+	}
+
+	ttl := otpPolicies[OTPPurposePasswordReset].TTL
+	if d := time.Until(stored.ExpiresAt); d <= 0 || d > ttl {
+		t.Fatalf("OTP berlaku %v lagi, want (0, %v]", d, ttl)
+	}
+}
+
+func TestOTPSendCooldownAndIssue(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+
+	if err := e.otp.Send(e.ctx, user, OTPPurposePasswordReset, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	first := e.lastOTP("alice@example.com")
+
+	var cooldownErr *OTPCooldownError
+	err := e.otp.Send(e.ctx, user, OTPPurposePasswordReset, "")
+	if !errors.As(err, &cooldownErr) {
+		t.Fatalf("Send kedua: got %v, want *OTPCooldownError", err)
+	}
+	if cooldown := otpPolicies[OTPPurposePasswordReset].ResendCooldown; cooldownErr.RetryAfter <= 0 || cooldownErr.RetryAfter > cooldown {
+		t.Fatalf("RetryAfter = %v, want (0, %v]", cooldownErr.RetryAfter, cooldown)
+	}
+
+	// cooldown per purpose
+	if err := e.otp.Send(e.ctx, user, OTPPurposeAccountUnlock, ""); err != nil {
+		t.Fatalf("Send purpose lain: %v", err)
+	}
+
+	// Issue (dipicu admin) tidak kena cooldown & menggantikan OTP lama
+	if err := e.otp.Issue(e.ctx, user, OTPPurposePasswordReset, ""); err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	second := e.lastOTP("alice@example.com")
+
+	var count int64
+	e.db.Model(&models.UserOTP{}).Where("user_id = ? AND purpose = ?", user.ID, OTPPurposePasswordReset).Count(&count)
+	if count != 1 {
+		t.Fatalf("jumlah OTP aktif = %d, want 1", count)
+	}
+
+	if first != second {
+		if _, err := e.otp.Verify(e.ctx, user.ID, OTPPurposePasswordReset, first); !errors.Is(err, ErrOTPIncorrect) {
+			t.Fatalf("OTP lama: got %v, want ErrOTPIncorrect", err)
+		}
+	}
+	if _, err := e.otp.Verify(e.ctx, user.ID, OTPPurposePasswordReset, second); err != nil {
+		t.Fatalf("OTP baru: %v", err)
+	}
+}
+
+func TestOTPVerifyAttemptLimit(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+
+	if err := e.otp.Send(e.ctx, user, OTPPurposePasswordReset, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	code := e.lastOTP("alice@example.com")
+
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	maxAttempts := otpPolicies[OTPPurposePasswordReset].MaxAttempts
+	for i := 1; i <= maxAttempts; i++ {
+		if _, err := e.otp.Verify(e.ctx, user.ID, OTPPurposePasswordReset, wrong); !errors.Is(err, ErrOTPIncorrect) {
+			t.Fatalf("percobaan ke-%d: got %v, want ErrOTPIncorrect", i, err)
+		}
+	}
+
+	// batas habis, kode benar pun ditolak
+	if _, err := e.otp.Verify(e.ctx, user.ID, OTPPurposePasswordReset, code); !errors.Is(err, ErrOTPTooManyAttempts) {
+		t.Fatalf("setelah batas: got %v, want ErrOTPTooManyAttempts", err)
+	}
+}
+
+func TestOTPVerifyRejectsExpiredAndOtherPurpose(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+
+	if err := e.otp.Send(e.ctx, user, OTPPurposePasswordReset, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	code := e.lastOTP("alice@example.com")
+
+	if _, err := e.otp.Verify(e.ctx, user.ID, OTPPurposeAccountUnlock, code); !errors.Is(err, ErrOTPInvalid) {
+		t.Fatalf("purpose lain: got %v, want ErrOTPInvalid", err)
+	}
+
+	e.db.Model(&models.UserOTP{}).Where("user_id = ?", user.ID).Update("expires_at", time.Now().Add(-time.Second))
+	if _, err := e.otp.Verify(e.ctx, user.ID, OTPPurposePasswordReset, code); !errors.Is(err, ErrOTPInvalid) {
+		t.Fatalf("OTP kadaluarsa: got %v, want ErrOTPInvalid", err)
+	}
+}
+
+func TestOTPConsumeOnce(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+
+	if err := e.otp.Send(e.ctx, user, OTPPurposePasswordReset, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	otp, err := e.otp.Verify(e.ctx, user.ID, OTPPurposePasswordReset, e.lastOTP("alice@example.com"))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	if err := e.otp.Consume(e.ctx, otp); err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	// request paralel yang sudah lolos Verify tidak bisa memakai OTP yang sama
+	if err := e.otp.Consume(e.ctx, otp); !errors.Is(err, ErrOTPInvalid) {
+		t.Fatalf("Consume kedua: got %v, want ErrOTPInvalid", err)
+	}
+}
+
+func TestOTPEmailExpiresWithOTP(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+
+	if err := e.otp.Send(e.ctx, user, OTPPurposePasswordReset, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var email models.EmailOutbox
+	if err := e.db.Where("template = ?", OTPPurposePasswordReset).Last(&email).Error; err != nil {
+		t.Fatal(err)
+	}
+	if email.ExpiresAt == nil {
+		t.Fatal("email OTP tanpa ExpiresAt")
+	}
+
+	// worker tidak mengirim email OTP yang sudah kadaluarsa
+	e.db.Model(&email).Update("expires_at", time.Now().Add(-time.Second))
+	e.mailer.Reset()
+	e.deliverEmails()
+
+	if n := len(e.mailer.Messages()); n != 0 {
+		t.Fatalf("terkirim %d email OTP kadaluarsa", n)
+	}
+	if err := e.db.First(&email, email.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if email.Status != models.EmailStatusDead || email.TextBody != "" || email.HTMLBody != "" {
+		t.Fatalf("email kadaluarsa: status %s, body kosong %v, want dead & body dibuang", email.Status, email.TextBody == "" && email.HTMLBody == "")
+	}
+}
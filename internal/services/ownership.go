@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mmgrapp/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAccountNotFound = errors.New("akun tidak ditemukan")
+	ErrAccountInactive = errors.New("akun sudah diarsipkan, aktifkan kembali untuk mencatat transaksi")
+	ErrPeriodNotFound  = errors.New("periode tidak ditemukan")
+)
+
+// validateAccountAndPeriod memastikan akun & periode yang direferensikan milik user
+func validateAccountAndPeriod(ctx context.Context, accountRepo repositories.AccountRepository, periodRepo repositories.PeriodRepository, userID, accountID, periodID int) error {
+	account, err := accountRepo.FindByID(ctx, accountID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAccountNotFound
+		}
+		return err
+	}
+	if !account.IsActive {
+		return ErrAccountInactive
+	}
+
+	if _, err := periodRepo.FindByID(ctx, periodID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPeriodNotFound
+		}
+		return err
+	}
+
+	return nil
+}
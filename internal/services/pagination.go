@@ -0,0 +1,20 @@
+package services
+
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// normalizePagination memberi nilai default page & limit yang valid
+func normalizePagination(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return page, limit
+}
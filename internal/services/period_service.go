@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/pkg/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPeriodOverlap         = errors.New("rentang tanggal bertabrakan dengan periode lain")
+	ErrPeriodIsDefault       = errors.New("periode default tidak dapat dihapus, pilih periode default lain terlebih dahulu")
+	ErrPeriodHasTransactions = errors.New("periode masih memiliki transaksi")
+)
+
+var monthNamesID = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+type PeriodService interface {
+	Create(ctx context.Context, userID int, req dto.PeriodRequest) (*models.Period, error)
+	List(ctx context.Context, userID int) ([]models.Period, error)
+	GetByID(ctx context.Context, userID, id int) (*models.Period, error)
+	GetDefault(ctx context.Context, userID int) (*models.Period, error)
+	Update(ctx context.Context, userID, id int, req dto.PeriodRequest) (*models.Period, error)
+	SetDefault(ctx context.Context, userID, id int) (*models.Period, error)
+	Delete(ctx context.Context, userID, id int) error
+	EnsureDefaultPeriod(ctx context.Context, userID int) (*models.Period, error)
+}
+
+type periodService struct {
+	repo repositories.PeriodRepository
+	uow  repositories.UnitOfWork
+}
+
+func NewPeriodService(periodRepo repositories.PeriodRepository, uow repositories.UnitOfWork) PeriodService {
+	return &periodService{
+		repo: periodRepo,
+		uow:  uow,
+	}
+}
+
+func (s *periodService) Create(ctx context.Context, userID int, req dto.PeriodRequest) (*models.Period, error) {
+	startDate, endDate, err := s.validateRange(ctx, userID, req.StartDate, req.EndDate, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// periode pertama otomatis jadi default
+	count, err := s.repo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	period := &models.Period{
+		UserID:    userID,
+		Name:      req.Name,
+		StartDate: startDate,
+		EndDate:   endDate,
+		IsDefault: req.IsDefault || count == 0,
+		CreatedBy: &userID,
+		UpdatedBy: &userID,
+	}
+
+	if err := s.repo.Create(ctx, period); err != nil {
+		return nil, err
+	}
+
+	return period, nil
+}
+
+func (s *periodService) List(ctx context.Context, userID int) ([]models.Period, error) {
+	return s.repo.FindAll(ctx, userID)
+}
+
+func (s *periodService) GetByID(ctx context.Context, userID, id int) (*models.Period, error) {
+	period, err := s.repo.FindByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPeriodNotFound
+		}
+		return nil, err
+	}
+
+	return period, nil
+}
+
+func (s *periodService) GetDefault(ctx context.Context, userID int) (*models.Period, error) {
+	period, err := s.repo.FindDefault(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPeriodNotFound
+		}
+		return nil, err
+	}
+
+	return period, nil
+}
+
+func (s *periodService) Update(ctx context.Context, userID, id int, req dto.PeriodRequest) (*models.Period, error) {
+	period, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate, err := s.validateRange(ctx, userID, req.StartDate, req.EndDate, id)
+	if err != nil {
+		return nil, err
+	}
+
+	period.Name = req.Name
+	period.StartDate = startDate
+	period.EndDate = endDate
+	period.UpdatedBy = &userID
+
+	// perubahan data & pemindahan default disimpan bersamaan
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, period); err != nil {
+			return err
+		}
+
+		// default hanya bisa dipindah, tidak bisa dilepas tanpa pengganti
+		if req.IsDefault && !period.IsDefault {
+			if err := s.repo.SetDefault(ctx, id, userID, userID); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrPeriodNotFound
+				}
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, userID, id)
+}
+
+func (s *periodService) SetDefault(ctx context.Context, userID, id int) (*models.Period, error) {
+	if err := s.repo.SetDefault(ctx, id, userID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPeriodNotFound
+		}
+		return nil, err
+	}
+
+	return s.GetByID(ctx, userID, id)
+}
+
+func (s *periodService) Delete(ctx context.Context, userID, id int) error {
+	period, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if period.IsDefault {
+		return ErrPeriodIsDefault
+	}
+
+	count, err := s.repo.CountTransactions(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPeriodHasTransactions
+	}
+
+	err = s.repo.Delete(ctx, id, userID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrPeriodNotFound
+	}
+
+	return err
+}
+
+// EnsureDefaultPeriod membuat periode bulan berjalan sebagai default jika user belum punya default.
+// Periode yang sudah menutupi bulan berjalan dipakai sebagai default, bukan dibuat duplikat.
+func (s *periodService) EnsureDefaultPeriod(ctx context.Context, userID int) (*models.Period, error) {
+	period, err := s.repo.FindDefault(ctx, userID)
+	if err == nil {
+		return period, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	endDate := startDate.AddDate(0, 1, -1)
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.FindOverlap(ctx, userID, startDate, endDate)
+		if err == nil {
+			if err := s.repo.SetDefault(ctx, existing.ID, userID, userID); err != nil {
+				return err
+			}
+			existing.IsDefault = true
+			period = existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		period = &models.Period{
+			UserID:    userID,
+			Name:      fmt.Sprintf("%s %d", monthNamesID[now.Month()-1], now.Year()),
+			StartDate: startDate,
+			EndDate:   endDate,
+			IsDefault: true,
+			CreatedBy: &userID,
+			UpdatedBy: &userID,
+		}
+		return s.repo.Create(ctx, period)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return period, nil
+}
+
+// validateRange parse tanggal lalu cek urutan & overlap dengan periode lain
+func (s *periodService) validateRange(ctx context.Context, userID int, start, end string, excludeID int) (time.Time, time.Time, error) {
+	startDate, err := utils.ParseDate(start)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("format start_date harus YYYY-MM-DD")
+	}
+
+	endDate, err := utils.ParseDate(end)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("format end_date harus YYYY-MM-DD")
+	}
+
+	if endDate.Before(startDate) {
+		return time.Time{}, time.Time{}, errors.New("end_date tidak boleh sebelum start_date")
+	}
+
+	overlap, err := s.repo.HasOverlap(ctx, userID, startDate, endDate, excludeID)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if overlap {
+		return time.Time{}, time.Time{}, ErrPeriodOverlap
+	}
+
+	return startDate, endDate, nil
+}
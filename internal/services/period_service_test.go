@@ -0,0 +1,41 @@
+package services
+
+import (
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"testing"
+	"time"
+)
+
+func TestEnsureDefaultPeriodReusesOverlappingPeriod(t *testing.T) {
+	e := newTestEnv(t)
+	user := e.createUser("alice", "password123")
+	periods := NewPeriodService(repositories.NewPeriodRepository(e.db), repositories.NewUnitOfWork(e.db))
+
+	// periode gajian yang menutupi bulan berjalan tapi belum jadi default
+	e.db.Where("user_id = ?", user.ID).Delete(&models.Period{})
+	now := time.Now()
+	payday := &models.Period{
+		UserID:    user.ID,
+		Name:      "Gajian",
+		StartDate: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, 0, -5),
+		EndDate:   time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, 1, -6),
+	}
+	if err := e.db.Create(payday).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	period, err := periods.EnsureDefaultPeriod(e.ctx, user.ID)
+	if err != nil {
+		t.Fatalf("EnsureDefaultPeriod: %v", err)
+	}
+	if period.ID != payday.ID || !period.IsDefault {
+		t.Fatalf("periode default = %+v, want periode Gajian (id %d) jadi default", period, payday.ID)
+	}
+
+	var count int64
+	e.db.Model(&models.Period{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("jumlah periode = %d, want 1", count)
+	}
+}
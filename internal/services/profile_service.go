@@ -1 +1,90 @@
 package services
+
+import (
+	"context"
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type ProfileService interface {
+	GetMyProfile(ctx context.Context, userID int) (*dto.ProfileResponse, error)
+	UpdateMyProfile(ctx context.Context, userID int, req dto.ProfileRequest) (*dto.ProfileResponse, error)
+}
+
+type profileService struct {
+	repo repositories.ProfileRepository
+}
+
+func NewProfileService(profileRepo repositories.ProfileRepository) ProfileService {
+	return &profileService{repo: profileRepo}
+}
+
+func (s *profileService) GetMyProfile(ctx context.Context, userID int) (*dto.ProfileResponse, error) {
+	profile, err := s.findOrCreate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewProfileResponse(profile), nil
+}
+
+func (s *profileService) UpdateMyProfile(ctx context.Context, userID int, req dto.ProfileRequest) (*dto.ProfileResponse, error) {
+	profile, err := s.findOrCreate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.FirstName = strings.TrimSpace(req.FirstName)
+	profile.MiddleName = strings.TrimSpace(req.MiddleName)
+	profile.LastName = strings.TrimSpace(req.LastName)
+	profile.FullName = buildFullName(profile.FirstName, profile.MiddleName, profile.LastName)
+	if req.Locale != "" {
+		profile.Locale = req.Locale
+	}
+	profile.UpdatedBy = &userID
+
+	if err := s.repo.Update(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	return dto.NewProfileResponse(profile), nil
+}
+
+// findOrCreate ambil profile user, buat baris kosong jika belum pernah ada
+func (s *profileService) findOrCreate(ctx context.Context, userID int) (*models.Profile, error) {
+	profile, err := s.repo.FindByUserID(ctx, userID)
+	if err == nil {
+		return profile, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	profile = &models.Profile{
+		UserID:    userID,
+		CreatedBy: &userID,
+		UpdatedBy: &userID,
+	}
+	if err := s.repo.Create(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// buildFullName gabung first, middle & last name yang tidak kosong
+func buildFullName(names ...string) string {
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		if name != "" {
+			parts = append(parts, name)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -1 +1,100 @@
 package services
+
+import (
+	"context"
+	"errors"
+	"mmgrapp/internal/dto"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var ErrSummaryFilterConflict = errors.New("period_id tidak dapat digabung dengan start_date / end_date")
+
+type SummaryService interface {
+	GetSummary(ctx context.Context, userID int, filter repositories.SummaryFilter) (*dto.SummaryResponse, error)
+}
+
+type summaryService struct {
+	repo       repositories.SummaryRepository
+	periodRepo repositories.PeriodRepository
+}
+
+func NewSummaryService(summaryRepo repositories.SummaryRepository, periodRepo repositories.PeriodRepository) SummaryService {
+	return &summaryService{
+		repo:       summaryRepo,
+		periodRepo: periodRepo,
+	}
+}
+
+// GetSummary ringkasan keuangan user, by period_id atau rentang tanggal (tidak
+// keduanya). Tanpa period_id maupun rentang tanggal, periode default yang dipakai.
+func (s *summaryService) GetSummary(ctx context.Context, userID int, filter repositories.SummaryFilter) (*dto.SummaryResponse, error) {
+	if filter.PeriodID != 0 && (filter.StartDate != nil || filter.EndDate != nil) {
+		return nil, ErrSummaryFilterConflict
+	}
+
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, errors.New("end_date tidak boleh sebelum start_date")
+	}
+
+	response := &dto.SummaryResponse{}
+
+	switch {
+	case filter.PeriodID != 0:
+		period, err := s.periodRepo.FindByID(ctx, filter.PeriodID, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrPeriodNotFound
+			}
+			return nil, err
+		}
+		response.PeriodID = period.ID
+		response.StartDate = period.StartDate.Format(utils.DateLayout)
+		response.EndDate = period.EndDate.Format(utils.DateLayout)
+
+	case filter.StartDate == nil && filter.EndDate == nil:
+		period, err := s.periodRepo.FindDefault(ctx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrPeriodNotFound
+			}
+			return nil, err
+		}
+		filter.PeriodID = period.ID
+		response.PeriodID = period.ID
+		response.StartDate = period.StartDate.Format(utils.DateLayout)
+		response.EndDate = period.EndDate.Format(utils.DateLayout)
+
+	default:
+		if filter.StartDate != nil {
+			response.StartDate = filter.StartDate.Format(utils.DateLayout)
+		}
+		if filter.EndDate != nil {
+			response.EndDate = filter.EndDate.Format(utils.DateLayout)
+		}
+	}
+
+	var err error
+
+	if response.TotalIncome, err = s.repo.TotalIncome(ctx, userID, filter); err != nil {
+		return nil, err
+	}
+	if response.TotalExpense, err = s.repo.TotalExpense(ctx, userID, filter); err != nil {
+		return nil, err
+	}
+	response.NetBalance = response.TotalIncome - response.TotalExpense
+
+	if response.Accounts, err = s.repo.AccountBalances(ctx, userID, filter); err != nil {
+		return nil, err
+	}
+	if response.IncomeCategories, err = s.repo.IncomeByCategory(ctx, userID, filter); err != nil {
+		return nil, err
+	}
+	if response.ExpenseCategories, err = s.repo.ExpenseByCategory(ctx, userID, filter); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"mmgrapp/internal/dto"
 	"mmgrapp/internal/models"
 	"mmgrapp/internal/repositories"
 	"mmgrapp/pkg/utils"
-	"time"
+	"strconv"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -18,17 +20,27 @@ type UserService interface {
 	VerifyEmailOTP(ctx context.Context, email, otp string) error
 	ResendOTP(ctx context.Context, email string, purpose string) error
 	GetUserByID(ctx context.Context, id int) (interface{}, error)
+	RequestEmailChange(ctx context.Context, userID int, newEmail, password string) error
+	ConfirmEmailChange(ctx context.Context, userID int, otp string) error
 }
 
 type userService struct {
-	repo    repositories.UserRepository
-	otpRepo repositories.OTPRepository
+	repo          repositories.UserRepository
+	otpService    OTPService
+	periodService PeriodService
+	guard         *loginGuard
+	outboxRepo    repositories.EmailOutboxRepository
+	uow           repositories.UnitOfWork
 }
 
-func NewUserService(userRepo repositories.UserRepository, otpRepo repositories.OTPRepository) UserService {
+func NewUserService(userRepo repositories.UserRepository, otpService OTPService, periodService PeriodService, loginAttemptRepo repositories.LoginAttemptRepository, outboxRepo repositories.EmailOutboxRepository, uow repositories.UnitOfWork) UserService {
 	return &userService{
-		repo:    userRepo,
-		otpRepo: otpRepo,
+		repo:          userRepo,
+		otpService:    otpService,
+		periodService: periodService,
+		guard:         &loginGuard{repo: loginAttemptRepo},
+		outboxRepo:    outboxRepo,
+		uow:           uow,
 	}
 }
 
@@ -54,36 +66,19 @@ func (s *userService) Register(ctx context.Context, username, email, password st
 		Password: string(hashedPassword),
 	}
 
-	// create user
-	if err := s.repo.Create(ctx, user); err != nil {
-		return nil, err
-	}
-
-	// generate OTP
-	otp, hashedOTP, err := utils.GenerateOTP()
-	if err != nil {
-		return nil, err
-	}
+	// user, OTP & email verifikasi dibuat dalam satu transaksi,
+	// gagal di langkah manapun tidak menyisakan user setengah jadi
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, user); err != nil {
+			return err
+		}
 
-	// delete OTP lama (optional)
-	s.otpRepo.DeleteOTP(ctx, user.ID, "email_verification")
-
-	// simpan OTP
-	err = s.otpRepo.Create(ctx, &models.UserOTP{
-		UserID:    user.ID,
-		OTP:       hashedOTP,
-		Purpose:   "email_verification",
-		ExpiresAt: time.Now().Add(5 * time.Minute),
+		return s.otpService.Send(ctx, user, OTPPurposeEmailVerification, "")
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	// kirim email
-	if err := utils.SendOTP(user.Email, otp); err != nil {
-		return nil, err
-	}
-
 	return user, nil
 }
 
@@ -94,43 +89,37 @@ func (s *userService) VerifyEmailOTP(ctx context.Context, email, otp string) err
 		return errors.New("email tidak ditemukan")
 	}
 
-	// 2. Get latest OTP for verification
-	storedOTP, err := s.otpRepo.FindValidOTP(ctx, user.ID, "email_verification")
+	// 2. Cek OTP (masa berlaku, batas percobaan & kode)
+	storedOTP, err := s.otpService.Verify(ctx, user.ID, OTPPurposeEmailVerification, otp)
 	if err != nil {
-		return errors.New("OTP tidak ditemukan atau sudah kadaluarsa")
-	}
-
-	// 3. Check expiry
-	if time.Now().After(storedOTP.ExpiresAt) {
-		return errors.New("OTP sudah kadaluarsa")
+		return err
 	}
 
-	// 4. Compare OTP
-	if err := bcrypt.CompareHashAndPassword([]byte(storedOTP.OTP), []byte(otp)); err != nil {
-		return errors.New("OTP salah")
-	}
+	// 3. Update user as verified + hapus OTP setelah dipakai
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.repo.VerifyUser(ctx, email); err != nil {
+			return err
+		}
 
-	// 5. Update user as verified
-	_, err = s.repo.VerifyUser(ctx, email)
+		return s.otpService.Consume(ctx, storedOTP)
+	})
 	if err != nil {
 		return err
 	}
 
-	// 6. Hapus OTP setelah dipakai
-	s.otpRepo.DeleteOTP(ctx, user.ID, "email_verification")
+	// 4. Buat periode bulan berjalan sebagai default
+	// user sudah terverifikasi, jadi kegagalan di sini cukup dicatat
+	if _, err := s.periodService.EnsureDefaultPeriod(ctx, user.ID); err != nil {
+		log.Printf("⚠️  Gagal membuat periode default untuk user %d: %v", user.ID, err)
+	}
 
 	return nil
 }
 
 func (s *userService) ResendOTP(ctx context.Context, email string, purpose string) error {
-	// cek purpose
-	const (
-		PurposeEmailVerification = "email_verification"
-		PurposePasswordReset     = "password_reset"
-	)
-
-	if purpose != PurposeEmailVerification && purpose != PurposePasswordReset {
-		return fmt.Errorf("invalid purpose")
+	// hanya purpose yang boleh diminta ulang secara publik
+	if !s.otpService.Resendable(purpose) {
+		return ErrInvalidOTPPurpose
 	}
 
 	// cek user
@@ -139,42 +128,105 @@ func (s *userService) ResendOTP(ctx context.Context, email string, purpose strin
 		return fmt.Errorf("user tidak ditemukan")
 	}
 
-	if user.IsVerified {
+	// OTP verifikasi tidak perlu lagi jika email sudah terverifikasi,
+	// OTP reset password tetap boleh diminta ulang
+	if purpose == OTPPurposeEmailVerification && user.IsVerified {
 		return fmt.Errorf("email sudah diverifikasi, silakan login")
 	}
 
-	// Generate OTP baru
-	otp, hashedOTP, err := utils.GenerateOTP()
+	return s.otpService.Send(ctx, user, purpose, "")
+}
+
+func (s *userService) GetUserByID(ctx context.Context, id int) (interface{}, error) {
+	user, err := s.repo.FindByIDWithProfile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	userResponse := dto.UserResponse{
+		ID:         user.ID,
+		Username:   user.Username,
+		Email:      user.Email,
+		IsVerified: user.IsVerified,
+		Profile:    dto.NewProfileResponse(user.Profile),
+	}
+
+	return userResponse, nil
+}
+
+// RequestEmailChange kirim OTP ke email baru. Email user baru diganti setelah
+// OTP dikonfirmasi lewat ConfirmEmailChange.
+func (s *userService) RequestEmailChange(ctx context.Context, userID int, newEmail, password string) error {
+	user, err := s.repo.FindByID(ctx, userID)
 	if err != nil {
 		return err
 	}
-	otpExpiresTime := time.Now().Add(5 * time.Minute)
 
-	// Update OTP di DB
-	if err := s.otpRepo.UpdateOTP(ctx, user.ID, purpose, hashedOTP, otpExpiresTime); err != nil {
+	// salah password ikut dihitung supaya sesi curian tidak bisa dipakai menebak password
+	userKey := strconv.Itoa(user.ID)
+	if err := s.guard.check(ctx, userLockPolicy, userKey); err != nil {
 		return err
 	}
+	if !utils.CheckPasswordHash(password, user.Password) {
+		if lockErr := s.guard.fail(ctx, userLockPolicy, userKey); lockErr != nil {
+			return lockErr
+		}
+		return ErrWrongPassword
+	}
+
+	newEmail = strings.TrimSpace(newEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		return errors.New("email baru sama dengan email saat ini")
+	}
 
-	// Kirim OTP via email
-	if err := utils.SendOTP(user.Email, otp); err != nil {
+	taken, err := s.repo.IsEmailTaken(ctx, newEmail)
+	if err != nil {
 		return err
 	}
+	if taken {
+		return errors.New("email sudah digunakan")
+	}
 
-	return nil
+	// permintaan baru menggantikan permintaan ganti email sebelumnya
+	return s.otpService.Send(ctx, user, OTPPurposeEmailChange, newEmail)
 }
 
-func (s *userService) GetUserByID(ctx context.Context, id int) (interface{}, error) {
-	user, err := s.repo.FindByID(ctx, id)
+// ConfirmEmailChange verifikasi OTP yang dikirim ke email baru lalu ganti email user
+func (s *userService) ConfirmEmailChange(ctx context.Context, userID int, otp string) error {
+	storedOTP, err := s.otpService.Verify(ctx, userID, OTPPurposeEmailChange, otp)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	userResponse := dto.UserResponse{
-		ID:         user.ID,
-		Username:   user.Username,
-		Email:      user.Email,
-		IsVerified: user.IsVerified,
+	// email bisa saja sudah dipakai user lain sejak OTP dikirim
+	taken, err := s.repo.IsEmailTaken(ctx, storedOTP.Target)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("email sudah digunakan")
 	}
 
-	return userResponse, nil
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	oldEmail := user.Email
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.otpService.Consume(ctx, storedOTP); err != nil {
+			return err
+		}
+
+		if err := s.repo.UpdateFields(ctx, userID, map[string]interface{}{
+			"email":      storedOTP.Target,
+			"updated_by": userID,
+		}); err != nil {
+			return err
+		}
+
+		// email lama diberi tahu, jaga-jaga jika perubahan dilakukan orang lain
+		queueSecurityAlert(ctx, s.outboxRepo, userLocale(ctx, s.repo, userID), oldEmail, user, securityEventEmailChanged, storedOTP.Target, "")
+		return nil
+	})
 }
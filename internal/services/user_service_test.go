@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"mmgrapp/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestRegisterSendsVerificationOTP(t *testing.T) {
+	e := newTestEnv(t)
+
+	user, err := e.users.Register(e.ctx, "alice", "alice@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if user.IsVerified {
+		t.Fatal("user baru tidak boleh langsung terverifikasi")
+	}
+
+	code := e.lastOTP("alice@example.com")
+
+	if err := e.users.VerifyEmailOTP(e.ctx, "alice@example.com", code); err != nil {
+		t.Fatalf("VerifyEmailOTP: %v", err)
+	}
+
+	verified, err := e.userRepo.FindByEmail(e.ctx, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verified.IsVerified {
+		t.Fatal("user belum terverifikasi setelah OTP benar")
+	}
+
+	// OTP sekali pakai
+	if err := e.users.VerifyEmailOTP(e.ctx, "alice@example.com", code); !errors.Is(err, ErrOTPInvalid) {
+		t.Fatalf("OTP bekas: got %v, want ErrOTPInvalid", err)
+	}
+}
+
+func TestRegisterDuplicateSendsNoEmail(t *testing.T) {
+	e := newTestEnv(t)
+	e.createUser("alice", "password123")
+	e.mailer.Reset()
+
+	if _, err := e.users.Register(e.ctx, "alice2", "alice@example.com", "password123"); err == nil {
+		t.Fatal("email duplikat harus ditolak")
+	}
+
+	e.deliverEmails()
+	if n := len(e.mailer.Messages()); n != 0 {
+		t.Fatalf("register gagal mengirim %d email", n)
+	}
+}
+
+func TestResendOTPSendsNewCode(t *testing.T) {
+	e := newTestEnv(t)
+
+	user, err := e.users.Register(e.ctx, "alice", "alice@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	first := e.lastOTP("alice@example.com")
+
+	// terlalu cepat → cooldown
+	var cooldownErr *OTPCooldownError
+	if err := e.users.ResendOTP(e.ctx, "alice@example.com", OTPPurposeEmailVerification); !errors.As(err, &cooldownErr) {
+		t.Fatalf("resend sebelum cooldown: got %v, want *OTPCooldownError", err)
+	}
+
+	e.skipOTPCooldown(user.ID, OTPPurposeEmailVerification)
+	if err := e.users.ResendOTP(e.ctx, "alice@example.com", OTPPurposeEmailVerification); err != nil {
+		t.Fatalf("ResendOTP: %v", err)
+	}
+	second := e.lastOTP("alice@example.com")
+
+	if n := len(e.mailer.Messages()); n != 2 {
+		t.Fatalf("jumlah email = %d, want 2", n)
+	}
+
+	// OTP lama diganti, hanya satu OTP aktif per purpose
+	var count int64
+	e.db.Model(&models.UserOTP{}).Where("user_id = ? AND purpose = ?", user.ID, OTPPurposeEmailVerification).Count(&count)
+	if count != 1 {
+		t.Fatalf("jumlah OTP aktif = %d, want 1", count)
+	}
+
+	if first != second {
+		if err := e.users.VerifyEmailOTP(e.ctx, "alice@example.com", first); !errors.Is(err, ErrOTPIncorrect) {
+			t.Fatalf("OTP lama: got %v, want ErrOTPIncorrect", err)
+		}
+	}
+	if err := e.users.VerifyEmailOTP(e.ctx, "alice@example.com", second); err != nil {
+		t.Fatalf("VerifyEmailOTP dengan OTP baru: %v", err)
+	}
+}
+
+func TestResendOTPRejectsVerifiedEmailAndUnknownPurpose(t *testing.T) {
+	e := newTestEnv(t)
+	e.createUser("alice", "password123")
+	e.mailer.Reset()
+
+	if err := e.users.ResendOTP(e.ctx, "alice@example.com", OTPPurposeEmailVerification); err == nil {
+		t.Fatal("resend verifikasi untuk email terverifikasi harus ditolak")
+	}
+	if err := e.users.ResendOTP(e.ctx, "alice@example.com", OTPPurposeEmailChange); !errors.Is(err, ErrInvalidOTPPurpose) {
+		t.Fatalf("purpose email_change: got %v, want ErrInvalidOTPPurpose", err)
+	}
+
+	// reset password tetap boleh diminta ulang
+	if err := e.users.ResendOTP(e.ctx, "alice@example.com", OTPPurposePasswordReset); err != nil {
+		t.Fatalf("resend password_reset: %v", err)
+	}
+	e.deliverEmails()
+
+	msgs := e.mailer.Messages()
+	if len(msgs) != 1 || msgs[0].To != "alice@example.com" {
+		t.Fatalf("email terkirim = %+v, want satu email ke alice@example.com", msgs)
+	}
+	if !strings.Contains(msgs[0].TextBody, e.lastOTP("alice@example.com")) {
+		t.Fatal("email reset tidak berisi OTP")
+	}
+}
@@ -0,0 +1,35 @@
+package services
+
+import "mmgrapp/internal/models"
+
+// StatusError error status akun beserta kode yang bisa dipakai client
+// untuk menampilkan pesan yang tepat
+type StatusError struct {
+	Code    string
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+var (
+	ErrUserInactive    = &StatusError{Code: "USER_INACTIVE", Message: "akun dinonaktifkan, silakan hubungi admin"}
+	ErrUserNotVerified = &StatusError{Code: "USER_NOT_VERIFIED", Message: "email belum diverifikasi"}
+	ErrUserNotFound    = &StatusError{Code: "USER_NOT_FOUND", Message: "akun tidak ditemukan atau sudah dihapus"}
+)
+
+// CheckUserStatus gerbang status akun yang dipakai login, refresh & middleware
+func CheckUserStatus(user *models.User) error {
+	if user == nil || user.DeletedAt.Valid {
+		return ErrUserNotFound
+	}
+	if !user.IsActive {
+		return ErrUserInactive
+	}
+	if !user.IsVerified {
+		return ErrUserNotVerified
+	}
+
+	return nil
+}
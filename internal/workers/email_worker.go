@@ -0,0 +1,135 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"mmgrapp/internal/models"
+	"mmgrapp/internal/repositories"
+	"mmgrapp/pkg/utils"
+	"time"
+)
+
+// EmailWorkerConfig pengaturan pengiriman email dari outbox
+type EmailWorkerConfig struct {
+	PollInterval time.Duration // jeda antar pengecekan outbox
+	BatchSize    int           // jumlah email per klaim
+	MaxAttempts  int           // setelah sekian gagal email dipindah ke dead
+	BaseBackoff  time.Duration // jeda percobaan ulang pertama, berlipat dua tiap gagal
+	MaxBackoff   time.Duration
+	StaleAfter   time.Duration // email "sending" lebih lama dari ini dianggap macet
+	SendTimeout  time.Duration // batas waktu satu pengiriman, harus di bawah StaleAfter
+}
+
+func DefaultEmailWorkerConfig() EmailWorkerConfig {
+	return EmailWorkerConfig{
+		PollInterval: 5 * time.Second,
+		BatchSize:    20,
+		MaxAttempts:  8,
+		BaseBackoff:  30 * time.Second,
+		MaxBackoff:   1 * time.Hour,
+		StaleAfter:   5 * time.Minute,
+		SendTimeout:  30 * time.Second,
+	}
+}
+
+// EmailWorker mengirim email di outbox lewat Mailer dengan retry exponential
+// backoff, email yang terus gagal dipindah ke dead untuk dicek admin
+type EmailWorker struct {
+	repo   repositories.EmailOutboxRepository
+	mailer utils.Mailer
+	cfg    EmailWorkerConfig
+}
+
+func NewEmailWorker(repo repositories.EmailOutboxRepository, mailer utils.Mailer, cfg EmailWorkerConfig) *EmailWorker {
+	return &EmailWorker{repo: repo, mailer: mailer, cfg: cfg}
+}
+
+// Run memproses outbox sampai ctx dibatalkan
+func (w *EmailWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// batch penuh berarti kemungkinan masih ada antrean, langsung lanjut
+		for w.ProcessBatch(ctx) == w.cfg.BatchSize && ctx.Err() == nil {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch kirim satu batch email yang sudah waktunya, mengembalikan jumlah
+// email yang diproses. Dipakai Run, bisa juga dipanggil langsung (mis. di test).
+func (w *EmailWorker) ProcessBatch(ctx context.Context) int {
+	now := time.Now()
+	emails, err := w.repo.ClaimDue(ctx, now, now.Add(-w.cfg.StaleAfter), w.cfg.BatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("⚠️ Gagal mengambil antrean email: %v", err)
+		}
+		return 0
+	}
+
+	for _, email := range emails {
+		w.deliver(ctx, email)
+	}
+
+	return len(emails)
+}
+
+func (w *EmailWorker) deliver(ctx context.Context, email models.EmailOutbox) {
+	// status akhir tetap disimpan walau server sedang shutdown
+	saveCtx := context.WithoutCancel(ctx)
+
+	// email kadaluarsa (mis. OTP) langsung dead tanpa dikirim
+	if email.ExpiresAt != nil && !time.Now().Before(*email.ExpiresAt) {
+		if err := w.repo.MarkFailed(saveCtx, email.ID, time.Now(), "kadaluarsa sebelum terkirim", true); err != nil {
+			log.Printf("⚠️ Gagal menyimpan status email #%d: %v", email.ID, err)
+		}
+		return
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, w.cfg.SendTimeout)
+	err := w.mailer.Send(sendCtx, utils.EmailMessage{
+		To:       email.ToEmail,
+		Subject:  email.Subject,
+		TextBody: email.TextBody,
+		HTMLBody: email.HTMLBody,
+	})
+	cancel()
+	if err == nil {
+		if err := w.repo.MarkSent(saveCtx, email.ID); err != nil {
+			log.Printf("⚠️ Gagal menandai email #%d terkirim: %v", email.ID, err)
+		}
+		return
+	}
+
+	dead := email.Attempts >= w.cfg.MaxAttempts
+	if dead {
+		log.Printf("❌ Email #%d (%s) ke %s gagal setelah %d percobaan: %v", email.ID, email.Template, email.ToEmail, email.Attempts, err)
+	}
+
+	nextAttemptAt := time.Now().Add(w.backoff(email.Attempts))
+	if err := w.repo.MarkFailed(saveCtx, email.ID, nextAttemptAt, err.Error(), dead); err != nil {
+		log.Printf("⚠️ Gagal menyimpan status email #%d: %v", email.ID, err)
+	}
+}
+
+// backoff base * 2^(attempts-1), dibatasi MaxBackoff, ditambah jitter s.d. 20%
+// supaya email yang gagal bersamaan tidak dicoba ulang serentak
+func (w *EmailWorker) backoff(attempts int) time.Duration {
+	delay := w.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < w.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.cfg.MaxBackoff {
+		delay = w.cfg.MaxBackoff
+	}
+
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
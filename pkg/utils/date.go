@@ -0,0 +1,11 @@
+package utils
+
+import "time"
+
+// DateLayout format tanggal yang dipakai di request API
+const DateLayout = "2006-01-02"
+
+// ParseDate mengubah string YYYY-MM-DD menjadi time.Time
+func ParseDate(value string) (time.Time, error) {
+	return time.ParseInLocation(DateLayout, value, time.Local)
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenDenylist daftar jti access token yang sudah dicabut sebelum expired.
+// Implementasi default in-memory (per proses), bisa diganti backend bersama
+// seperti Redis lewat SetTokenDenylist.
+type TokenDenylist interface {
+	Add(ctx context.Context, jti string, expiresAt time.Time) error
+	// TryAdd seperti Add tapi cek & simpan dalam satu langkah atomik, false jika
+	// jti sudah ada. Dipakai untuk token sekali pakai.
+	TryAdd(ctx context.Context, jti string, expiresAt time.Time) (bool, error)
+	Contains(ctx context.Context, jti string) (bool, error)
+}
+
+type MemoryDenylist struct {
+	mu    sync.RWMutex
+	items map[string]time.Time
+}
+
+func NewMemoryDenylist() *MemoryDenylist {
+	return &MemoryDenylist{items: make(map[string]time.Time)}
+}
+
+var denylist TokenDenylist = NewMemoryDenylist()
+
+// SetTokenDenylist mengganti backend denylist
+func SetTokenDenylist(d TokenDenylist) {
+	denylist = d
+}
+
+// CurrentTokenDenylist backend denylist yang sedang dipakai
+func CurrentTokenDenylist() TokenDenylist {
+	return denylist
+}
+
+// Add menyimpan jti sampai token aslinya expired, sekalian membuang entry yang sudah lewat
+func (d *MemoryDenylist) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := d.TryAdd(ctx, jti, expiresAt)
+	return err
+}
+
+func (d *MemoryDenylist) TryAdd(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key, exp := range d.items {
+		if now.After(exp) {
+			delete(d.items, key)
+		}
+	}
+
+	if _, exists := d.items[jti]; exists {
+		return false, nil
+	}
+	if expiresAt.After(now) {
+		d.items[jti] = expiresAt
+	}
+
+	return true, nil
+}
+
+func (d *MemoryDenylist) Contains(ctx context.Context, jti string) (bool, error) {
+	d.mu.RLock()
+	exp, ok := d.items[jti]
+	d.mu.RUnlock()
+
+	return ok && time.Now().Before(exp), nil
+}
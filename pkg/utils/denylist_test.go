@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryDenylist(t *testing.T) {
+	ctx := context.Background()
+	d := NewMemoryDenylist()
+
+	if err := d.Add(ctx, "active", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Add(ctx, "expired", time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		jti  string
+		want bool
+	}{
+		{"active", true},
+		{"expired", false}, // token sudah expired, tidak perlu disimpan
+		{"unknown", false},
+	}
+	for _, tt := range tests {
+		got, err := d.Contains(ctx, tt.jti)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.jti, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryDenylistDropsExpiredEntries(t *testing.T) {
+	ctx := context.Background()
+	d := NewMemoryDenylist()
+
+	d.items["old"] = time.Now().Add(-time.Minute)
+	if err := d.Add(ctx, "new", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := d.items["old"]; ok {
+		t.Fatal("entry expired tidak dibersihkan saat Add")
+	}
+}
+
+func TestMemoryDenylistTryAdd(t *testing.T) {
+	ctx := context.Background()
+	d := NewMemoryDenylist()
+	exp := time.Now().Add(time.Hour)
+
+	if added, err := d.TryAdd(ctx, "jti", exp); err != nil || !added {
+		t.Fatalf("TryAdd pertama = %v, %v; want true, nil", added, err)
+	}
+	if added, err := d.TryAdd(ctx, "jti", exp); err != nil || added {
+		t.Fatalf("TryAdd kedua = %v, %v; want false, nil", added, err)
+	}
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "strings"
+
+// DeviceLabelFromUserAgent tebakan sederhana nama perangkat dari User-Agent
+func DeviceLabelFromUserAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+
+	switch {
+	case ua == "":
+		return "Unknown device"
+	case strings.Contains(ua, "iphone"):
+		return "iPhone"
+	case strings.Contains(ua, "ipad"):
+		return "iPad"
+	case strings.Contains(ua, "android"):
+		return "Android"
+	case strings.Contains(ua, "windows"):
+		return "Windows"
+	case strings.Contains(ua, "mac os"):
+		return "Mac"
+	case strings.Contains(ua, "linux"):
+		return "Linux"
+	case strings.Contains(ua, "okhttp"), strings.Contains(ua, "dart"):
+		return "Mobile app"
+	default:
+		return "Unknown device"
+	}
+}
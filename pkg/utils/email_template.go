@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+//go:embed all:templates/email
+var emailTemplateFS embed.FS
+
+// DefaultLocale bahasa email jika user belum memilih / locale tidak didukung
+const DefaultLocale = "id"
+
+// SupportedLocales bahasa yang punya template email
+var SupportedLocales = []string{"id", "en"}
+
+// NormalizeLocale ambil kode bahasa dari locale seperti "en-US", fallback ke DefaultLocale
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if len(locale) > 2 {
+		locale = locale[:2]
+	}
+
+	for _, supported := range SupportedLocales {
+		if locale == supported {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var (
+	emailTemplateMu    sync.Mutex
+	emailTemplateCache = map[string]*emailTemplate{}
+)
+
+// RenderEmail render template email name dalam locale tertentu. Setiap template
+// terdiri dari <name>.txt.tmpl (blok "subject" & "body") dan <name>.html.tmpl
+// (blok "content" di dalam _layout.html.tmpl). Field To diisi pemanggil.
+func RenderEmail(locale, name string, data interface{}) (EmailMessage, error) {
+	tmpl, err := loadEmailTemplate(NormalizeLocale(locale), name)
+	if err != nil {
+		return EmailMessage{}, err
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return EmailMessage{}, err
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "body", data); err != nil {
+		return EmailMessage{}, err
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return EmailMessage{}, err
+	}
+
+	return EmailMessage{
+		Subject:  strings.TrimSpace(subject.String()),
+		TextBody: strings.TrimSpace(text.String()) + "\n",
+		HTMLBody: html.String(),
+	}, nil
+}
+
+func loadEmailTemplate(locale, name string) (*emailTemplate, error) {
+	key := locale + "/" + name
+
+	emailTemplateMu.Lock()
+	defer emailTemplateMu.Unlock()
+
+	if tmpl, ok := emailTemplateCache[key]; ok {
+		return tmpl, nil
+	}
+
+	dir := "templates/email/" + locale + "/"
+
+	// partial (_*.tmpl) ikut di-parse supaya template bisa memakai blok bersama
+	text, err := texttemplate.New(name).ParseFS(emailTemplateFS, dir+"_*.txt.tmpl", dir+name+".txt.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("template email %s: %w", key, err)
+	}
+
+	html, err := htmltemplate.New(name).ParseFS(emailTemplateFS, dir+"_*.html.tmpl", dir+name+".html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("template email %s: %w", key, err)
+	}
+
+	tmpl := &emailTemplate{text: text, html: html}
+	emailTemplateCache[key] = tmpl
+	return tmpl, nil
+}
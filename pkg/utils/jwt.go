@@ -1,60 +1,81 @@
 package utils
 
 import (
-	"os"
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte(os.Getenv("SECRET_KEY"))
+var errSignerNotConfigured = errors.New("JWT signer belum dikonfigurasi")
 
 type JWTClaims struct {
-	UserID  int    `json:"user_id"`
-	Type    string `json:"type"`
-	IsAdmin bool   `json:"is_admin"`
+	UserID int      `json:"user_id"`
+	Type   string   `json:"type"`
+	Roles  []string `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// generate JWT access
-func GenerateAccessToken(UserID int, IsAdmin bool) (string, error) {
-	expirationTime := time.Now().Add(1 * time.Hour) // berlaku 1 jam
+// AccessTokenTTL masa berlaku access token
+const AccessTokenTTL = 1 * time.Hour
+
+// generate JWT access, mengembalikan token beserta jti-nya
+func GenerateAccessToken(userID int, roles []string) (string, string, error) {
+	jti, err := GenerateRandomID(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	expirationTime := time.Now().Add(AccessTokenTTL) // berlaku 1 jam
 	claims := &JWTClaims{
-		UserID:  UserID,
-		Type:    "access",
-		IsAdmin: IsAdmin,
+		UserID: userID,
+		Type:   "access",
+		Roles:  roles,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	token, err := signToken(claims)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, jti, nil
 }
 
 // generate JWT refresh
 func GenerateRefreshTokenJWT(userID int) (string, error) {
 	expirationTime := time.Now().Add(7 * 24 * time.Hour)
 
+	// jti acak supaya token hasil rotasi di detik yang sama tetap unik
+	jti, err := GenerateRandomID(16)
+	if err != nil {
+		return "", err
+	}
+
 	claims := &JWTClaims{
 		UserID: userID,
 		Type:   "refresh",
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return signToken(claims)
 }
 
 // verify JWT
 func VerifyJWT(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+	if signer == nil {
+		return nil, errSignerNotConfigured
+	}
+
+	token, err := signer.Verify(tokenString, &JWTClaims{})
 
 	if err != nil {
 		return nil, err
@@ -67,3 +88,34 @@ func VerifyJWT(tokenString string) (*JWTClaims, error) {
 
 	return claims, nil
 }
+
+func signToken(claims jwt.Claims) (string, error) {
+	if signer == nil {
+		return "", errSignerNotConfigured
+	}
+	return signer.Sign(claims)
+}
+
+// MFAChallengeTTL masa berlaku token tantangan MFA antara password & kode TOTP
+const MFAChallengeTTL = 5 * time.Minute
+
+// GenerateMFAChallengeToken token sementara setelah password benar, hanya bisa
+// ditukar dengan access/refresh token lewat verifikasi kode MFA
+func GenerateMFAChallengeToken(userID int) (string, error) {
+	jti, err := GenerateRandomID(16)
+	if err != nil {
+		return "", err
+	}
+
+	claims := &JWTClaims{
+		UserID: userID,
+		Type:   "mfa",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(MFAChallengeTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return signToken(claims)
+}
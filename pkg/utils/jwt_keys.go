@@ -0,0 +1,236 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signer penandatangan & verifikator JWT. Implementasi default adalah KeySet
+// (key PEM di disk), bisa diganti misalnya dengan KMS lewat SetSigner.
+type Signer interface {
+	Sign(claims jwt.Claims) (string, error)
+	Verify(tokenString string, claims jwt.Claims) (*jwt.Token, error)
+	JWKS() JWKS
+}
+
+// JWK public key dalam format RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer // nil untuk key yang hanya dipakai verifikasi
+	public  crypto.PublicKey
+}
+
+// KeySet kumpulan key RS256 / EdDSA yang diidentifikasi dengan kid.
+// Hanya key aktif yang dipakai sign, semua key dipakai verify, sehingga
+// rotasi cukup dengan menambah key baru lalu memindah key aktif.
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+var signer Signer
+
+// SetSigner mengganti signer yang dipakai GenerateAccessToken dkk
+func SetSigner(s Signer) {
+	signer = s
+}
+
+// CurrentSigner signer yang sedang aktif
+func CurrentSigner() Signer {
+	return signer
+}
+
+// LoadJWTKeys memuat semua file *.pem di dir (nama file = kid) lalu memasang
+// KeySet sebagai signer. activeKID boleh kosong jika hanya ada satu private key.
+func LoadJWTKeys(dir, activeKID string) error {
+	keySet, err := NewKeySetFromDir(dir, activeKID)
+	if err != nil {
+		return err
+	}
+
+	SetSigner(keySet)
+	return nil
+}
+
+func NewKeySetFromDir(dir, activeKID string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("tidak ada JWT key (*.pem) di %s", dir)
+	}
+	sort.Strings(files)
+
+	keySet := &KeySet{keys: make(map[string]*signingKey)}
+	var privateKIDs []string
+
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := parseSigningKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", kid, err)
+		}
+
+		keySet.keys[kid] = key
+		if key.private != nil {
+			privateKIDs = append(privateKIDs, kid)
+		}
+	}
+
+	if activeKID == "" {
+		if len(privateKIDs) != 1 {
+			return nil, errors.New("JWT_ACTIVE_KID wajib diisi jika private key tidak tepat satu")
+		}
+		activeKID = privateKIDs[0]
+	}
+
+	active, ok := keySet.keys[activeKID]
+	if !ok || active.private == nil {
+		return nil, fmt.Errorf("private key untuk kid %q tidak ditemukan", activeKID)
+	}
+	keySet.active = active
+
+	return keySet, nil
+}
+
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.active.method, claims)
+	token.Header["kid"] = k.active.kid
+	return token.SignedString(k.active.private)
+}
+
+func (k *KeySet) Verify(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		key, ok := k.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("kid %q tidak dikenal", kid)
+		}
+
+		// algoritma harus sesuai dengan jenis key, cegah alg confusion
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("algoritma %s tidak sesuai untuk kid %q", token.Method.Alg(), kid)
+		}
+
+		return key.public, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}))
+}
+
+func (k *KeySet) JWKS() JWKS {
+	kids := make([]string, 0, len(k.keys))
+	for kid := range k.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		jwks.Keys = append(jwks.Keys, k.keys[kid].jwk())
+	}
+
+	return jwks
+}
+
+func (key *signingKey) jwk() JWK {
+	jwk := JWK{
+		Kid: key.kid,
+		Use: "sig",
+		Alg: key.method.Alg(),
+	}
+
+	switch pub := key.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
+
+// parseSigningKey menerima PKCS#8 / PKCS#1 private key atau PKIX public key (verify only)
+func parseSigningKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("format PEM tidak valid")
+	}
+
+	var parsed interface{}
+	var err error
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("tipe PEM %q tidak didukung", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{kid: kid}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case ed25519.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case *rsa.PublicKey:
+		key.method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PublicKey:
+		key.method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, errors.New("hanya key RSA dan Ed25519 yang didukung")
+	}
+
+	if rsaKey, ok := key.public.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < 2048 {
+		return nil, errors.New("RSA key minimal 2048 bit")
+	}
+
+	return key, nil
+}
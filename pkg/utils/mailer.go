@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/gomail.v2"
+)
+
+// EmailMessage email keluar. TextBody wajib, HTMLBody opsional (multipart alternative).
+type EmailMessage struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// Mailer pengirim email yang di-inject ke service. Tersedia backend SMTP,
+// file (dev lokal) dan memory (test).
+type Mailer interface {
+	Send(ctx context.Context, msg EmailMessage) error
+}
+
+// buildMessage susun EmailMessage jadi pesan MIME gomail
+func buildMessage(from string, msg EmailMessage) *gomail.Message {
+	m := gomail.NewMessage()
+	m.SetHeader("From", from)
+	m.SetHeader("To", msg.To)
+	m.SetHeader("Subject", msg.Subject)
+	m.SetBody("text/plain", msg.TextBody)
+	if msg.HTMLBody != "" {
+		m.AddAlternative("text/html", msg.HTMLBody)
+	}
+	return m
+}
+
+/* ================= SMTP ================= */
+
+// SMTP TLS mode: STARTTLS setelah koneksi plain (umumnya port 587) atau TLS
+// langsung sejak awal koneksi (umumnya port 465)
+const (
+	SMTPTLSStartTLS = "starttls"
+	SMTPTLSImplicit = "tls"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string // contoh: "MMGRAPP <no-reply@example.com>"
+	TLSMode  string // SMTPTLSStartTLS / SMTPTLSImplicit, kosong = tebak dari port
+	Insecure bool   // lewati verifikasi sertifikat, hanya untuk server dev
+}
+
+type SMTPMailer struct {
+	from   string
+	dialer *gomail.Dialer
+}
+
+func NewSMTPMailer(cfg SMTPConfig) (*SMTPMailer, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("SMTP host belum dikonfigurasi")
+	}
+
+	dialer := gomail.NewDialer(cfg.Host, cfg.Port, cfg.Username, cfg.Password)
+
+	switch cfg.TLSMode {
+	case "":
+		// gomail: port 465 = TLS langsung, selain itu STARTTLS jika didukung server
+	case SMTPTLSStartTLS:
+		dialer.SSL = false
+	case SMTPTLSImplicit:
+		dialer.SSL = true
+	default:
+		return nil, fmt.Errorf("SMTP TLS mode %q tidak dikenal", cfg.TLSMode)
+	}
+
+	dialer.TLSConfig = &tls.Config{
+		ServerName:         cfg.Host,
+		InsecureSkipVerify: cfg.Insecure,
+	}
+
+	return &SMTPMailer{from: cfg.From, dialer: dialer}, nil
+}
+
+// Send kirim lewat SMTP dengan batas waktu dari ctx. gomail tidak menerima ctx,
+// jadi pengiriman jalan di goroutine dan Send berhenti menunggu saat ctx selesai.
+func (m *SMTPMailer) Send(ctx context.Context, msg EmailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- m.dialer.DialAndSend(buildMessage(m.from, msg))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/* ================= FILE ================= */
+
+// FileMailer simpan email sebagai file .eml di dir dan catat di log, untuk dev lokal
+type FileMailer struct {
+	from string
+	dir  string
+}
+
+func NewFileMailer(from, dir string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileMailer{from: from, dir: dir}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg EmailMessage) error {
+	suffix, err := GenerateRandomID(4)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), suffix)
+	path := filepath.Join(m.dir, name)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := buildMessage(m.from, msg).WriteTo(file); err != nil {
+		return err
+	}
+
+	log.Printf("📧 Email ke %s (%s) disimpan di %s", msg.To, msg.Subject, path)
+	return nil
+}
+
+/* ================= MEMORY ================= */
+
+// MemoryMailer simpan email di memory supaya test bisa memeriksa email terkirim
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []EmailMessage
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg EmailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages salinan semua email yang sudah dikirim
+func (m *MemoryMailer) Messages() []EmailMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]EmailMessage(nil), m.messages...)
+}
+
+// Last email terakhir yang dikirim ke alamat to
+func (m *MemoryMailer) Last(to string) (EmailMessage, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].To == to {
+			return m.messages[i], true
+		}
+	}
+	return EmailMessage{}, false
+}
+
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = nil
+}
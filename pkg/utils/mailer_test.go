@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSMTPMailerSendRespectsContext(t *testing.T) {
+	// server SMTP yang menerima koneksi tapi tidak pernah membalas
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	mailer, err := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: "no-reply@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := EmailMessage{To: "alice@example.com", Subject: "Tes", TextBody: "halo"}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := mailer.Send(cancelled, msg); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ctx dibatalkan: got %v, want context.Canceled", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := mailer.Send(ctx, msg); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("server diam: got %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Send menunggu %v, tidak berhenti saat deadline ctx", elapsed)
+	}
+}
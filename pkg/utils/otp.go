@@ -3,6 +3,7 @@ package utils
 import (
 	"crypto/rand"
 	"fmt"
+	"sync"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -19,8 +20,28 @@ func GenerateOTP() (string, string, error) {
 	otp := fmt.Sprintf("%06d", num) // selalu 6 digit, leading zero included
 
 	// hash OTP untuk simpan
-	hashedOTPBytes, _ := bcrypt.GenerateFromPassword([]byte(otp), bcrypt.DefaultCost)
-	hashedOTP := string(hashedOTPBytes)
+	hashedOTPBytes, err := bcrypt.GenerateFromPassword([]byte(otp), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", err
+	}
+
+	return otp, string(hashedOTPBytes), nil
+}
+
+// dummyOTPHash pembanding saat OTP tidak ada, dibuat sekali saat pertama dipakai
+var dummyOTPHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("000000"), bcrypt.DefaultCost)
+	return hash
+})
+
+// CheckOTP bandingkan OTP dengan hash-nya dalam waktu konstan. Hash kosong
+// (OTP tidak ditemukan) tetap melewati bcrypt supaya waktu respon-nya sama
+// dengan OTP yang salah.
+func CheckOTP(otp, hashedOTP string) bool {
+	if hashedOTP == "" {
+		_ = bcrypt.CompareHashAndPassword(dummyOTPHash(), []byte(otp))
+		return false
+	}
 
-	return otp, hashedOTP, nil
+	return bcrypt.CompareHashAndPassword([]byte(hashedOTP), []byte(otp)) == nil
 }
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate kapasitas bucket: maksimal Limit request per Period, diisi ulang merata
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// ParseRate membaca format "<jumlah>/<durasi>", contoh "5/15m" atau "20/1h"
+func ParseRate(value string) (Rate, error) {
+	limitPart, periodPart, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("format rate %q tidak valid, contoh: 5/15m", value)
+	}
+
+	limit, err := strconv.Atoi(limitPart)
+	if err != nil || limit <= 0 {
+		return Rate{}, fmt.Errorf("jumlah pada rate %q harus bilangan positif", value)
+	}
+
+	period, err := time.ParseDuration(periodPart)
+	if err != nil || period <= 0 {
+		return Rate{}, fmt.Errorf("durasi pada rate %q tidak valid", value)
+	}
+
+	return Rate{Limit: limit, Period: period}, nil
+}
+
+// RateLimiter backend pembatas request per key. Implementasi default token
+// bucket in-memory (per proses), bisa diganti backend bersama seperti Redis
+// lewat SetRateLimiter.
+type RateLimiter interface {
+	// Allow ambil satu token dari bucket key, retryAfter terisi jika ditolak
+	Allow(ctx context.Context, key string, rate Rate) (allowed bool, retryAfter time.Duration, err error)
+}
+
+type tokenBucket struct {
+	tokens   float64
+	last     time.Time
+	capacity float64
+	perSec   float64
+}
+
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+var rateLimiter RateLimiter = NewMemoryRateLimiter()
+
+// SetRateLimiter mengganti backend rate limiter
+func SetRateLimiter(l RateLimiter) {
+	rateLimiter = l
+}
+
+// CurrentRateLimiter backend rate limiter yang sedang dipakai
+func CurrentRateLimiter() RateLimiter {
+	return rateLimiter
+}
+
+func (l *MemoryRateLimiter) Allow(ctx context.Context, key string, rate Rate) (bool, time.Duration, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{
+			tokens:   float64(rate.Limit),
+			last:     now,
+			capacity: float64(rate.Limit),
+			perSec:   float64(rate.Limit) / rate.Period.Seconds(),
+		}
+		l.buckets[key] = bucket
+	}
+
+	bucket.refill(now)
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0, nil
+	}
+
+	retryAfter := time.Duration((1 - bucket.tokens) / bucket.perSec * float64(time.Second))
+	return false, retryAfter, nil
+}
+
+// sweep buang bucket yang sudah penuh lagi (setara belum pernah dipakai), maksimal sekali per menit
+func (l *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	for key, bucket := range l.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= bucket.capacity {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.perSec
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+}
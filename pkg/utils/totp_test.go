@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// vektor uji RFC 6238 (SHA-1), 6 digit terakhir dari kode 8 digit di RFC
+func TestValidateTOTPRFC6238(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		now := time.Unix(tt.unix, 0)
+		step, ok := ValidateTOTP(secret, tt.code, now)
+		if !ok {
+			t.Errorf("ValidateTOTP(%s) pada %d ditolak", tt.code, tt.unix)
+			continue
+		}
+		if step != TOTPStep(now) {
+			t.Errorf("step = %d, want %d", step, TOTPStep(now))
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	current := TOTPStep(now)
+
+	for offset := int64(-1); offset <= 1; offset++ {
+		if _, ok := ValidateTOTP(secret, hotp(key, current+offset), now); !ok {
+			t.Errorf("kode langkah %+d ditolak", offset)
+		}
+	}
+	for _, offset := range []int64{-2, 2} {
+		if _, ok := ValidateTOTP(secret, hotp(key, current+offset), now); ok {
+			t.Errorf("kode langkah %+d diterima", offset)
+		}
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 3 {
+		t.Fatalf("jumlah code = %d, want 3", len(codes))
+	}
+
+	tests := map[string]string{
+		"abcde-fghij":   "abcde-fghij",
+		"ABCDEFGHIJ":    "abcde-fghij",
+		" abcde fghij ": "abcde-fghij",
+		codes[0]:        codes[0],
+	}
+	for input, want := range tests {
+		if got := NormalizeRecoveryCode(input); got != want {
+			t.Errorf("NormalizeRecoveryCode(%q) = %q, want %q", input, got, want)
+		}
+	}
+}